@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const diffContext = 3
+
+func runConfig(args []string) {
+	if len(args) == 0 {
+		configUsageExit()
+	}
+
+	switch args[0] {
+	case "migrate":
+		runMigrate(args[1:])
+	case "paths":
+		runPaths()
+	default:
+		configUsageExit()
+	}
+}
+
+func configUsageExit() {
+	fmt.Fprintln(os.Stderr, "usage: route-keeper config migrate [--dry-run] [--config FILE]")
+	fmt.Fprintln(os.Stderr, "       route-keeper config paths")
+	os.Exit(2)
+}
+
+func runPaths() {
+	dirs := setupDirs()
+	fmt.Printf("Profiles:      %s\n", dirs.ProfilesPath())
+	fmt.Printf("Settings:      %s\n", dirs.SettingsPath())
+	fmt.Printf("History:       %s\n", dirs.HistoryPath())
+	fmt.Printf("Alert state:   %s (daemon), %s (TUI)\n", dirs.AlertsPath(), dirs.TUIAlertsPath())
+	fmt.Printf("Notifications: %s\n", dirs.NotificationLogPath())
+}
+
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("config migrate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Show the changes without writing them")
+	configPath := fs.String("config", "", configUsage)
+	fs.Parse(args)
+
+	setupDirs()
+	profilesManager := newProfilesManager(*configPath)
+	migrations, err := profilesManager.Migrate(*dryRun)
+	for _, migration := range migrations {
+		printMigration(os.Stdout, migration)
+	}
+	if err != nil {
+		log.Fatalf("Error migrating profiles: %v", err)
+	}
+
+	switch {
+	case len(migrations) == 0:
+		fmt.Printf("All profile files are at version %d.\n", models.ConfigVersion)
+	case *dryRun:
+		fmt.Println("Dry run: no files were changed.")
+	default:
+		fmt.Printf("Migrated %d file(s); previous versions were saved as <file>.bak.1\n", len(migrations))
+	}
+}
+
+func printMigration(w io.Writer, migration models.FileMigration) {
+	fmt.Fprintf(w, "%s: version %d -> %d\n", migration.Path, migration.From, migration.To)
+	for _, step := range migration.Steps {
+		fmt.Fprintf(w, "  * %s\n", step)
+	}
+	fmt.Fprintf(w, "--- %s (version %d)\n", migration.Path, migration.From)
+	fmt.Fprintf(w, "+++ %s (version %d)\n", migration.Path, migration.To)
+
+	lines := models.DiffLines(
+		strings.TrimSuffix(string(migration.Before), "\n"),
+		strings.TrimSuffix(string(migration.After), "\n"),
+	)
+
+	last := -1
+	for i, line := range lines {
+		if !nearChange(lines, i) {
+			continue
+		}
+		if last >= 0 && i > last+1 {
+			fmt.Fprintln(w, "@@")
+		}
+		last = i
+
+		prefix := " "
+		switch line.Op {
+		case models.DiffInsert:
+			prefix = "+"
+		case models.DiffDelete:
+			prefix = "-"
+		}
+		fmt.Fprintln(w, prefix+line.Text)
+	}
+	fmt.Fprintln(w)
+}
+
+func nearChange(lines []models.DiffLine, i int) bool {
+	for j := max(i-diffContext, 0); j <= min(i+diffContext, len(lines)-1); j++ {
+		if lines[j].Op != models.DiffEqual {
+			return true
+		}
+	}
+	return false
+}
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/route-keeper/internal/daemon"
 	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
 	"github.com/lutefd/route-keeper/internal/ui"
 )
 
@@ -19,6 +27,8 @@ var (
 	GoVersion = "1.24.1"
 )
 
+const configUsage = "Profiles file to use instead of the default one (.json, .yaml, .yml or .toml)"
+
 func printVersion() {
 	fmt.Printf("Route Keeper - API Monitoring Tool\n")
 	fmt.Printf("Version:    %s\n", Version)
@@ -29,22 +39,199 @@ func printVersion() {
 	os.Exit(0)
 }
 
+func maintenanceWindows(settings models.Settings) []models.MaintenanceWindow {
+	var windows []models.MaintenanceWindow
+	for _, w := range settings.Maintenance {
+		if err := w.Validate(); err != nil {
+			log.Printf("Warning: Ignoring %v", err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	return windows
+}
+
+func newAlerter(path string, settings models.Settings, logger *log.Logger) (*notify.Alerter, func()) {
+	alerter := notify.NewAlerter(path, settings.Escalation)
+	if err := alerter.Load(); err != nil {
+		log.Printf("Warning: Could not load alert state: %v", err)
+	}
+	alerter.AddChannel(notify.ChannelHooks, notify.NewHookRunner(settings.Hooks, logger))
+
+	closeFn := func() {}
+	if settings.Email != nil {
+		email, err := notify.NewEmailNotifier(*settings.Email, logger)
+		if err != nil {
+			log.Printf("Warning: Email alerts disabled: %v", err)
+		} else {
+			alerter.AddChannel(notify.ChannelEmail, email)
+			closeFn = func() {
+				if err := email.Close(); err != nil {
+					logger.Printf("email notification failed: %v", err)
+				}
+			}
+		}
+	}
+
+	return alerter, closeFn
+}
+
+func setupDirs() models.Dirs {
+	dirs, err := models.DefaultDirs()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := dirs.Ensure(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	moves, err := models.MigrateLegacyDir(dirs)
+	for _, move := range moves {
+		log.Printf("Moved %s to %s", move.From, move.To)
+	}
+	if err != nil {
+		log.Printf("Warning: Could not move legacy files: %v", err)
+	}
+	return dirs
+}
+
+func newProfilesManager(path string) *models.ProfilesManager {
+	if path == "" {
+		profilesManager, err := models.NewProfilesManager()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return profilesManager
+	}
+	if _, err := models.FormatForPath(path); err != nil {
+		log.Fatalf("Error: --config: %v", err)
+	}
+	return models.NewProfilesManagerForFile(path)
+}
+
+func reportConflicts(pm *models.ProfilesManager) {
+	for _, conflict := range pm.Conflicts() {
+		log.Printf("Warning: %s", conflict)
+	}
+}
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", daemon.DefaultAddr, "Address for the local control API")
+	historyLimit := fs.Int("history", 1000, "Number of results to keep per profile")
+	configPath := fs.String("config", "", configUsage)
+	fs.Parse(args)
+
+	dirs := setupDirs()
+	profilesManager := newProfilesManager(*configPath)
+	if err := profilesManager.LoadProfiles(); err != nil {
+		var invalid *models.InvalidProfileError
+		if !errors.As(err, &invalid) {
+			log.Fatalf("Error loading profiles: %v", err)
+		}
+		log.Printf("Warning: %v", err)
+	}
+	reportConflicts(profilesManager)
+
+	history, err := daemon.NewHistory(dirs.HistoryPath(), *historyLimit)
+	if err != nil {
+		log.Fatalf("Error opening history: %v", err)
+	}
+
+	settings, err := models.LoadSettings(dirs.SettingsPath())
+	if err != nil {
+		log.Printf("Warning: Could not load settings: %v", err)
+	}
+
+	server := daemon.NewServer(profilesManager, history)
+	server.SetMaintenanceWindows(maintenanceWindows(settings))
+	alerter, closeNotifiers := newAlerter(dirs.AlertsPath(), settings, log.Default())
+	defer closeNotifiers()
+	server.SetAlerter(alerter)
+
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		<-sigs
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down: %v", err)
+		}
+	}()
+
+	log.Printf("route-keeper daemon listening on %s", *addr)
+	if err := server.ListenAndServe(*addr); err != nil {
+		closeNotifiers()
+		log.Fatalf("Error running daemon: %v", err)
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfig(os.Args[2:])
+		return
+	}
+
 	versionFlag := flag.Bool("version", false, "Print version information and exit")
+	attachFlag := flag.String("attach", "", "Attach to a running daemon (e.g. "+daemon.DefaultAddr+")")
+	configFlag := flag.String("config", "", configUsage)
 	flag.Parse()
 
 	if *versionFlag {
 		printVersion()
 	}
 
-	profilesManager := models.NewProfilesManager()
-	if err := profilesManager.LoadProfiles(); err != nil {
-		log.Printf("Warning: Could not load profiles: %v", err)
+	dirs := setupDirs()
+
+	var m *ui.MainModel
+	if *attachFlag != "" {
+		client := daemon.NewClient(*attachFlag)
+		if err := client.Ping(); err != nil {
+			log.Fatalf("Error attaching to daemon at %s: %v", *attachFlag, err)
+		}
+		m = ui.NewAttachedModel(client)
+	} else {
+		profilesManager := newProfilesManager(*configFlag)
+		if err := profilesManager.LoadProfiles(); err != nil {
+			log.Printf("Warning: Could not load profiles: %v", err)
+		}
+		reportConflicts(profilesManager)
+		m = ui.NewMainModel(profilesManager)
+	}
+
+	settings, err := models.LoadSettings(dirs.SettingsPath())
+	if err != nil {
+		log.Printf("Warning: Could not load settings: %v", err)
 	}
+	m.PingService.SetMaintenanceWindows(maintenanceWindows(settings))
 
-	m := ui.NewMainModel(profilesManager)
+	output := ui.NewTerminalOutput(os.Stdout)
+	terminal := notify.NewTerminalNotifier(settings.Notifications, output)
+	if m.Daemon != nil {
+		m.Alerts.AddChannel(notify.ChannelTerminal, terminal)
+	} else {
+		logger := log.New(io.Discard, "", 0)
+		notifyLog, err := os.OpenFile(dirs.NotificationLogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Printf("Warning: Could not open notification log: %v", err)
+		} else {
+			defer notifyLog.Close()
+			logger = log.New(notifyLog, "", log.LstdFlags)
+		}
+
+		alerter, closeNotifiers := newAlerter(dirs.TUIAlertsPath(), settings, logger)
+		defer closeNotifiers()
+		alerter.AddChannel(notify.ChannelTerminal, terminal)
+		m.Alerts = alerter
+	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(output))
 	if _, err := p.Run(); err != nil {
 		log.Fatalf("Error running program: %v", err)
 	}
@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
+)
+
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	mu       sync.Mutex
+	profiles []models.Profile
+}
+
+func NewClient(addr string) *Client {
+	baseURL := strings.TrimSuffix(addr, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 45 * time.Second},
+	}
+}
+
+func (c *Client) Addr() string {
+	return c.baseURL
+}
+
+func (c *Client) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("daemon: %s", apiErr.Error)
+		}
+		return fmt.Errorf("daemon returned %d", resp.StatusCode)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func profilePath(name string, suffix string) string {
+	return "/api/profiles/" + url.PathEscape(name) + suffix
+}
+
+func (c *Client) Ping() error {
+	return c.do("GET", "/api/health", nil, nil)
+}
+
+func (c *Client) Refresh() error {
+	var profiles []models.Profile
+	if err := c.do("GET", "/api/profiles", nil, &profiles); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.profiles = profiles
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) GetProfiles() []models.Profile {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.profiles
+}
+
+func (c *Client) AddProfile(profile models.Profile) error {
+	exists := false
+	for _, p := range c.GetProfiles() {
+		if p.Name == profile.Name {
+			exists = true
+			break
+		}
+	}
+
+	var err error
+	if exists {
+		err = c.do("PUT", profilePath(profile.Name, ""), profile, nil)
+	} else {
+		err = c.do("POST", "/api/profiles", profile, nil)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Refresh()
+}
+
+func (c *Client) UpdateProfile(name string, profile models.Profile) error {
+	if err := c.do("PUT", profilePath(name, ""), profile, nil); err != nil {
+		return err
+	}
+	return c.Refresh()
+}
+
+func (c *Client) DeleteProfile(name string) error {
+	if err := c.do("DELETE", profilePath(name, ""), nil, nil); err != nil {
+		return err
+	}
+	return c.Refresh()
+}
+
+func (c *Client) Check(name string) (models.PingRecord, error) {
+	var record models.PingRecord
+	err := c.do("POST", profilePath(name, "/check"), nil, &record)
+	return record, err
+}
+
+func (c *Client) History(name string, limit int) ([]models.PingRecord, error) {
+	var records []models.PingRecord
+	err := c.do("GET", profilePath(name, "/history?limit="+strconv.Itoa(limit)), nil, &records)
+	return records, err
+}
+
+func (c *Client) Stats(name string) (Stats, error) {
+	var stats Stats
+	err := c.do("GET", profilePath(name, "/stats"), nil, &stats)
+	return stats, err
+}
+
+func (c *Client) Alert(name string) (notify.AlertState, error) {
+	var state notify.AlertState
+	err := c.do("GET", profilePath(name, "/alert"), nil, &state)
+	return state, err
+}
+
+func (c *Client) Acknowledge(name string, d time.Duration) (notify.AlertState, error) {
+	var state notify.AlertState
+	err := c.do("POST", profilePath(name, "/ack"), silenceRequest{Minutes: int(d.Minutes())}, &state)
+	return state, err
+}
+
+func (c *Client) Silence(name string, d time.Duration) (notify.AlertState, error) {
+	var state notify.AlertState
+	err := c.do("POST", profilePath(name, "/silence"), silenceRequest{Minutes: int(d.Minutes())}, &state)
+	return state, err
+}
+
+func (c *Client) Unsilence(name string) (notify.AlertState, error) {
+	var state notify.AlertState
+	err := c.do("DELETE", profilePath(name, "/silence"), nil, &state)
+	return state, err
+}
@@ -0,0 +1,261 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const defaultHistoryLimit = 1000
+
+type Stats struct {
+	Profile       string             `json:"profile"`
+	Total         int                `json:"total"`
+	Successful    int                `json:"successful"`
+	Failed        int                `json:"failed"`
+	Maintenance   int                `json:"maintenance"`
+	Uptime        float64            `json:"uptime"`
+	AvgDurationMs int64              `json:"avg_duration_ms"`
+	MinDurationMs int64              `json:"min_duration_ms"`
+	MaxDurationMs int64              `json:"max_duration_ms"`
+	P95DurationMs int64              `json:"p95_duration_ms"`
+	Since         time.Time          `json:"since"`
+	Last          *models.PingRecord `json:"last,omitempty"`
+}
+
+type History struct {
+	mu       sync.Mutex
+	records  map[string][]models.PingRecord
+	limit    int
+	filePath string
+	file     *os.File
+	appended int
+}
+
+func NewHistory(filePath string, limit int) (*History, error) {
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+
+	h := &History{
+		records:  make(map[string][]models.PingRecord),
+		limit:    limit,
+		filePath: filePath,
+	}
+
+	if filePath == "" {
+		return h, nil
+	}
+
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	if err := h.rewrite(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *History) rewrite() error {
+	if h.file != nil {
+		if err := h.file.Close(); err != nil {
+			return err
+		}
+		h.file = nil
+	}
+	if err := h.compact(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(h.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	h.file = file
+	h.appended = 0
+	return nil
+}
+
+func (h *History) retained() int {
+	total := 0
+	for _, records := range h.records {
+		total += len(records)
+	}
+	return total
+}
+
+func (h *History) load() error {
+	file, err := os.Open(h.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record models.PingRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		h.append(record)
+	}
+	return scanner.Err()
+}
+
+func (h *History) compact() error {
+	var all []models.PingRecord
+	for _, records := range h.records {
+		all = append(all, records...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Timestamp.Before(all[j].Timestamp)
+	})
+
+	tmpPath := h.filePath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range all {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, h.filePath)
+}
+
+func (h *History) append(record models.PingRecord) {
+	records := append(h.records[record.Profile], record)
+	if len(records) > h.limit {
+		records = records[len(records)-h.limit:]
+	}
+	h.records[record.Profile] = records
+}
+
+func (h *History) Add(record models.PingRecord) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.append(record)
+
+	if h.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := h.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	h.appended++
+	if h.appended > max(h.retained(), h.limit) {
+		return h.rewrite()
+	}
+	return nil
+}
+
+func (h *History) Recent(profile string, limit int) []models.PingRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := h.records[profile]
+	if limit <= 0 || limit > len(records) {
+		limit = len(records)
+	}
+
+	recent := make([]models.PingRecord, 0, limit)
+	for i := len(records) - 1; i >= len(records)-limit; i-- {
+		recent = append(recent, records[i])
+	}
+	return recent
+}
+
+func (h *History) Forget(profile string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.records[profile]; !ok {
+		return nil
+	}
+	delete(h.records, profile)
+
+	if h.file == nil {
+		return nil
+	}
+	return h.rewrite()
+}
+
+func (h *History) Stats(profile string) Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := h.records[profile]
+	stats := Stats{Profile: profile, Total: len(records)}
+	if len(records) == 0 {
+		return stats
+	}
+
+	durations := make([]int64, 0, len(records))
+	var total int64
+	for _, record := range records {
+		if record.Maintenance != "" {
+			stats.Maintenance++
+			continue
+		}
+		if record.Success {
+			stats.Successful++
+		}
+		durations = append(durations, record.DurationMs)
+		total += record.DurationMs
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	last := records[len(records)-1]
+	stats.Failed = stats.Total - stats.Successful - stats.Maintenance
+	if len(durations) > 0 {
+		stats.Uptime = float64(stats.Successful) / float64(len(durations)) * 100
+		stats.AvgDurationMs = total / int64(len(durations))
+		stats.MinDurationMs = durations[0]
+		stats.MaxDurationMs = durations[len(durations)-1]
+		stats.P95DurationMs = durations[(len(durations)*95+99)/100-1]
+	}
+	stats.Since = records[0].Timestamp
+	stats.Last = &last
+
+	return stats
+}
+
+func (h *History) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	err := h.file.Close()
+	h.file = nil
+	return err
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(profile string, success bool, durationMs int64, at time.Time) models.PingRecord {
+	return models.PingRecord{
+		Profile:    profile,
+		Timestamp:  at,
+		Success:    success,
+		StatusCode: 200,
+		DurationMs: durationMs,
+	}
+}
+
+func TestHistory_PersistsAndTrims(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	start := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+
+	h, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, h.Add(record("api", true, int64(i), start.Add(time.Duration(i)*time.Minute))))
+	}
+	require.NoError(t, h.Add(record("other", false, 1, start)))
+	require.NoError(t, h.Close())
+
+	reopened, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	recent := reopened.Recent("api", 0)
+	require.Len(t, recent, 3)
+	assert.Equal(t, int64(4), recent[0].DurationMs)
+	assert.Equal(t, int64(2), recent[2].DurationMs)
+
+	assert.Len(t, reopened.Recent("api", 1), 1)
+	assert.Len(t, reopened.Recent("other", 10), 1)
+
+	require.NoError(t, reopened.Forget("other"))
+	assert.Empty(t, reopened.Recent("other", 10))
+}
+
+func TestHistory_ForgetSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	start := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+
+	h, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	require.NoError(t, h.Add(record("api", true, 1, start)))
+	require.NoError(t, h.Add(record("old", true, 1, start)))
+	require.NoError(t, h.Forget("old"))
+	require.NoError(t, h.Add(record("api", true, 2, start.Add(time.Minute))))
+	require.NoError(t, h.Close())
+
+	reopened, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Empty(t, reopened.Recent("old", 10))
+	assert.Len(t, reopened.Recent("api", 10), 2)
+}
+
+func TestHistory_CompactsWhileRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	start := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+
+	h, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	defer h.Close()
+	for i := 0; i < 50; i++ {
+		require.NoError(t, h.Add(record("api", true, int64(i), start.Add(time.Duration(i)*time.Minute))))
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		lines++
+	}
+	assert.LessOrEqual(t, lines, 6)
+}
+
+func TestHistory_Stats(t *testing.T) {
+	h, err := NewHistory("", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Profile: "api"}, h.Stats("api"))
+
+	start := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 10; i++ {
+		h.Add(record("api", i != 3, int64(i*10), start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	stats := h.Stats("api")
+	assert.Equal(t, 10, stats.Total)
+	assert.Equal(t, 9, stats.Successful)
+	assert.Equal(t, 1, stats.Failed)
+	assert.InDelta(t, 90.0, stats.Uptime, 0.001)
+	assert.Equal(t, int64(55), stats.AvgDurationMs)
+	assert.Equal(t, int64(10), stats.MinDurationMs)
+	assert.Equal(t, int64(100), stats.MaxDurationMs)
+	assert.Equal(t, int64(100), stats.P95DurationMs)
+	assert.Equal(t, start.Add(time.Minute), stats.Since)
+	require.NotNil(t, stats.Last)
+	assert.Equal(t, int64(100), stats.Last.DurationMs)
+}
+
+func TestHistory_StatsExcludeMaintenance(t *testing.T) {
+	h, err := NewHistory("", 0)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+	h.Add(record("api", true, 10, start))
+	h.Add(record("api", false, 20, start.Add(time.Minute)))
+
+	down := record("api", false, 5000, start.Add(2*time.Minute))
+	down.Maintenance = "upgrade"
+	h.Add(down)
+	h.Add(down)
+
+	stats := h.Stats("api")
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 1, stats.Successful)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, 2, stats.Maintenance)
+	assert.InDelta(t, 50.0, stats.Uptime, 0.001)
+	assert.Equal(t, int64(20), stats.MaxDurationMs)
+	assert.Equal(t, "upgrade", stats.Last.Maintenance)
+
+	h.Add(models.PingRecord{Profile: "batch", Timestamp: start, Maintenance: "upgrade"})
+	stats = h.Stats("batch")
+	assert.Equal(t, 1, stats.Maintenance)
+	assert.Zero(t, stats.Failed)
+	assert.Zero(t, stats.Uptime)
+}
@@ -0,0 +1,520 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
+	"github.com/lutefd/route-keeper/internal/scheduler"
+)
+
+const DefaultAddr = "127.0.0.1:7878"
+
+const profileReloadInterval = 2 * time.Second
+
+type Server struct {
+	mu         sync.Mutex
+	profiles   *models.ProfilesManager
+	pinger     *models.PingService
+	history    *History
+	scheduler  *scheduler.Scheduler
+	httpServer *http.Server
+	stopWatch  context.CancelFunc
+	hosts      []string
+
+	alerts *notify.Alerter
+}
+
+func NewServer(pm *models.ProfilesManager, history *History) *Server {
+	s := &Server{
+		profiles: pm,
+		pinger:   models.NewPingService(),
+		history:  history,
+		alerts:   notify.NewAlerter("", nil),
+	}
+	s.scheduler = scheduler.New(func(profile models.Profile) {
+		s.check(profile)
+	}, log.Default())
+	return s
+}
+
+func (s *Server) SetMaintenanceWindows(windows []models.MaintenanceWindow) {
+	s.pinger.SetMaintenanceWindows(windows)
+}
+
+func (s *Server) SetAlerter(a *notify.Alerter) {
+	s.alerts = a
+}
+
+func (s *Server) Start() {
+	s.mu.Lock()
+	profiles := append([]models.Profile(nil), s.profiles.GetProfiles()...)
+	s.mu.Unlock()
+
+	for _, profile := range profiles {
+		s.schedule(profile)
+	}
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	s.allowHost(listener.Addr().String())
+	s.httpServer = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopWatch = cancel
+	go s.watchProfiles(ctx)
+
+	err := s.httpServer.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.allowHost(addr)
+	return s.Serve(listener)
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.stopWatch != nil {
+		s.stopWatch()
+	}
+	s.scheduler.Stop()
+
+	var err error
+	if s.httpServer != nil {
+		err = s.httpServer.Shutdown(ctx)
+	}
+	if closeErr := s.history.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (s *Server) watchProfiles(ctx context.Context) {
+	ticker := time.NewTicker(profileReloadInterval)
+	defer ticker.Stop()
+
+	var lastErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := s.ReloadProfiles()
+		switch {
+		case err != nil && err.Error() != lastErr:
+			log.Printf("Reloading profiles: %v", err)
+		case changed && err == nil:
+			log.Printf("Reloaded profiles")
+		}
+		lastErr = ""
+		if err != nil {
+			lastErr = err.Error()
+		}
+	}
+}
+
+func (s *Server) ReloadProfiles() (bool, error) {
+	s.mu.Lock()
+	before := s.profiles.GetProfiles()
+	changed, err := s.profiles.ReloadIfChanged()
+	after := s.profiles.GetProfiles()
+	s.mu.Unlock()
+
+	if !changed {
+		return false, err
+	}
+
+	updated, removed := models.DiffProfiles(before, after)
+	for _, name := range removed {
+		s.forget(name)
+	}
+	for _, profile := range updated {
+		s.schedule(profile)
+	}
+	return true, err
+}
+
+func (s *Server) schedule(profile models.Profile) {
+	if err := s.scheduler.Schedule(profile); err != nil {
+		log.Printf("Could not schedule %s: %v", profile.Name, err)
+	}
+}
+
+func (s *Server) forget(name string) {
+	s.scheduler.Unschedule(name)
+	if err := s.history.Forget(name); err != nil {
+		log.Printf("Could not clear history for %s: %v", name, err)
+	}
+	if err := s.alerts.Forget(name); err != nil {
+		log.Printf("Could not clear alert state for %s: %v", name, err)
+	}
+}
+
+func (s *Server) check(profile models.Profile) models.PingRecord {
+	result := s.pinger.Ping(profile)
+	record := models.NewPingRecord(profile.Name, result)
+	s.history.Add(record)
+
+	if err := s.alerts.Observe(profile, result); err != nil {
+		log.Printf("Alert for %s failed: %v", profile.Name, err)
+	}
+	return record
+}
+
+func (s *Server) findProfile(name string) (models.Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, profile := range s.profiles.GetProfiles() {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return models.Profile{}, false
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.HandleFunc("GET /api/profiles", s.handleListProfiles)
+	mux.HandleFunc("POST /api/profiles", s.handleCreateProfile)
+	mux.HandleFunc("GET /api/profiles/{name}", s.handleGetProfile)
+	mux.HandleFunc("PUT /api/profiles/{name}", s.handleUpdateProfile)
+	mux.HandleFunc("DELETE /api/profiles/{name}", s.handleDeleteProfile)
+	mux.HandleFunc("POST /api/profiles/{name}/check", s.handleCheck)
+	mux.HandleFunc("GET /api/profiles/{name}/history", s.handleHistory)
+	mux.HandleFunc("GET /api/profiles/{name}/stats", s.handleStats)
+	mux.HandleFunc("GET /api/profiles/{name}/alert", s.handleAlert)
+	mux.HandleFunc("POST /api/profiles/{name}/ack", s.handleAcknowledge)
+	mux.HandleFunc("POST /api/profiles/{name}/silence", s.handleSilence)
+	mux.HandleFunc("DELETE /api/profiles/{name}/silence", s.handleUnsilence)
+	return s.guard(mux)
+}
+
+func (s *Server) allowHost(addr string) {
+	s.hosts = append(s.hosts, strings.ToLower(hostname(addr)))
+}
+
+func (s *Server) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+	for _, allowed := range s.hosts {
+		if host == allowed {
+			return true
+		}
+		if ip != nil && (allowed == "" || net.ParseIP(allowed).IsUnspecified()) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.Trim(hostport, "[]")
+}
+
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.hostAllowed(hostname(r.Host)) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host == "" || !s.hostAllowed(u.Hostname()) {
+				writeError(w, http.StatusForbidden, fmt.Errorf("origin %q is not allowed", origin))
+				return
+			}
+		}
+		if r.ContentLength != 0 {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, errors.New("request body must be application/json"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListProfiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	profiles := append([]models.Profile{}, s.profiles.GetProfiles()...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, profiles)
+}
+
+func (s *Server) handleGetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, ok := s.findProfile(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func decodeProfile(r *http.Request) (models.Profile, error) {
+	var profile models.Profile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		return profile, fmt.Errorf("invalid profile: %w", err)
+	}
+	if err := profile.Validate(); err != nil {
+		return profile, fmt.Errorf("invalid profile: %w", err)
+	}
+	return profile, nil
+}
+
+var errHooksNotAllowed = errors.New("invalid profile: hooks can only be set in the profiles file or settings.json")
+
+func sameHooks(a, b []models.HookConfig) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (s *Server) handleCreateProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := decodeProfile(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(profile.Hooks) > 0 {
+		writeError(w, http.StatusBadRequest, errHooksNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	for _, existing := range s.profiles.GetProfiles() {
+		if existing.Name == profile.Name {
+			s.mu.Unlock()
+			writeError(w, http.StatusConflict, fmt.Errorf("profile %q already exists", profile.Name))
+			return
+		}
+	}
+	err = s.profiles.AddProfile(profile)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.schedule(profile)
+	writeJSON(w, http.StatusCreated, profile)
+}
+
+func (s *Server) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	profile, err := decodeProfile(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	existing, ok := s.findProfile(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+	if !sameHooks(existing.Hooks, profile.Hooks) {
+		writeError(w, http.StatusBadRequest, errHooksNotAllowed)
+		return
+	}
+	if _, ok := s.findProfile(profile.Name); ok && profile.Name != name {
+		writeError(w, http.StatusConflict, fmt.Errorf("profile %q already exists", profile.Name))
+		return
+	}
+
+	s.mu.Lock()
+	err = s.profiles.UpdateProfile(name, profile)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if profile.Name != name {
+		s.forget(name)
+	}
+	s.schedule(profile)
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func (s *Server) handleDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	s.mu.Lock()
+	err := s.profiles.DeleteProfile(name)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	s.forget(name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	profile, ok := s.findProfile(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.check(profile))
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", raw))
+			return
+		}
+		limit = parsed
+	}
+
+	writeJSON(w, http.StatusOK, s.history.Recent(name, limit))
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.history.Stats(name))
+}
+
+type silenceRequest struct {
+	Minutes int `json:"minutes"`
+}
+
+func decodeSilence(r *http.Request) (time.Duration, error) {
+	var req silenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.Minutes <= 0 {
+		return 0, fmt.Errorf("invalid request: minutes must be positive")
+	}
+	return time.Duration(req.Minutes) * time.Minute, nil
+}
+
+func (s *Server) handleAlert(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alerts.State(name))
+}
+
+func (s *Server) handleAcknowledge(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+
+	d, err := decodeSilence(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.alerts.Acknowledge(name, d); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alerts.State(name))
+}
+
+func (s *Server) handleSilence(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+
+	d, err := decodeSilence(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.alerts.Silence(name, d); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alerts.State(name))
+}
+
+func (s *Server) handleUnsilence(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.findProfile(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile not found"))
+		return
+	}
+
+	if err := s.alerts.Unsilence(name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alerts.State(name))
+}
@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDaemon(t *testing.T) (*Server, *Client) {
+	pm := models.NewProfilesManagerForFile(filepath.Join(t.TempDir(), "profiles.json"))
+	history, err := NewHistory("", 0)
+	require.NoError(t, err)
+
+	server := NewServer(pm, history)
+	api := httptest.NewServer(server.Handler())
+	t.Cleanup(func() {
+		server.scheduler.Stop()
+		api.Close()
+	})
+
+	return server, NewClient(api.URL)
+}
+
+func TestServer_ProfileCRUDAndChecks(t *testing.T) {
+	var hits int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	_, client := newTestDaemon(t)
+	require.NoError(t, client.Ping())
+	require.NoError(t, client.Refresh())
+	assert.Empty(t, client.GetProfiles())
+
+	profile := models.Profile{Name: "api v1", BaseURL: target.URL, Route: "/health", Interval: 60}
+	require.NoError(t, client.AddProfile(profile))
+	require.Len(t, client.GetProfiles(), 1)
+
+	err := client.do("POST", "/api/profiles", profile, nil)
+	assert.ErrorContains(t, err, "already exists")
+
+	record, err := client.Check("api v1")
+	require.NoError(t, err)
+	assert.True(t, record.Success)
+	assert.Equal(t, http.StatusOK, record.StatusCode)
+
+	history, err := client.History("api v1", 10)
+	require.NoError(t, err)
+	assert.NotEmpty(t, history)
+	assert.Equal(t, "api v1", history[0].Profile)
+
+	stats, err := client.Stats("api v1")
+	require.NoError(t, err)
+	assert.Equal(t, len(history), stats.Total)
+	assert.Equal(t, 100.0, stats.Uptime)
+
+	profile.Route = "/ready"
+	require.NoError(t, client.AddProfile(profile))
+	assert.Equal(t, "/ready", client.GetProfiles()[0].Route)
+
+	require.NoError(t, client.DeleteProfile("api v1"))
+	assert.Empty(t, client.GetProfiles())
+
+	_, err = client.Check("api v1")
+	assert.ErrorContains(t, err, "profile not found")
+	assert.Positive(t, atomic.LoadInt32(&hits))
+}
+
+func TestServer_RejectsInvalidProfiles(t *testing.T) {
+	_, client := newTestDaemon(t)
+
+	err := client.do("POST", "/api/profiles", map[string]string{"name": "missing url"}, nil)
+	assert.ErrorContains(t, err, "base_url")
+
+	err = client.do("POST", "/api/profiles", models.Profile{Name: "api", BaseURL: "localhost:8080", Interval: 5}, nil)
+	assert.ErrorContains(t, err, "base_url: must start with http:// or https://")
+
+	require.NoError(t, client.AddProfile(models.Profile{Name: "api", BaseURL: "http://localhost:8080", Interval: 5}))
+	require.NoError(t, client.AddProfile(models.Profile{Name: "web", BaseURL: "http://localhost:8081", Interval: 5}))
+	err = client.UpdateProfile("web", models.Profile{Name: "api", BaseURL: "http://localhost:8081", Interval: 5})
+	assert.ErrorContains(t, err, `profile "api" already exists`)
+
+	_, err = client.History("nope", 5)
+	assert.ErrorContains(t, err, "profile not found")
+}
+
+func TestServer_RejectsHooksFromAPI(t *testing.T) {
+	server, client := newTestDaemon(t)
+	hooks := []models.HookConfig{{Command: "touch /tmp/pwned"}}
+
+	err := client.AddProfile(models.Profile{Name: "api", BaseURL: "http://localhost:8080", Interval: 60, Hooks: hooks})
+	assert.ErrorContains(t, err, "hooks can only be set")
+	require.NoError(t, client.Refresh())
+	assert.Empty(t, client.GetProfiles())
+
+	require.NoError(t, client.AddProfile(models.Profile{Name: "api", BaseURL: "http://localhost:8080", Interval: 60}))
+	err = client.UpdateProfile("api", models.Profile{Name: "api", BaseURL: "http://localhost:8080", Interval: 60, Hooks: hooks})
+	assert.ErrorContains(t, err, "hooks can only be set")
+
+	onDisk := []models.HookConfig{{Command: "notify-send down", On: "down"}}
+	require.NoError(t, server.profiles.AddProfile(models.Profile{Name: "web", BaseURL: "http://localhost:8081", Interval: 60, Hooks: onDisk}))
+	require.NoError(t, client.UpdateProfile("web", models.Profile{Name: "web", BaseURL: "http://localhost:8082", Interval: 60, Hooks: onDisk}))
+	err = client.UpdateProfile("web", models.Profile{Name: "web", BaseURL: "http://localhost:8082", Interval: 60, Hooks: hooks})
+	assert.ErrorContains(t, err, "hooks can only be set")
+}
+
+func TestServer_RequestHygiene(t *testing.T) {
+	server, client := newTestDaemon(t)
+	handler := server.Handler()
+	body := `{"name":"api","base_url":"http://localhost:8080","interval":60}`
+
+	serve := func(host string, headers map[string]string) int {
+		req := httptest.NewRequest("POST", "http://"+host+"/api/profiles", strings.NewReader(body))
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, serve("127.0.0.1:7878", map[string]string{"Content-Type": "text/plain"}))
+	assert.Equal(t, http.StatusUnsupportedMediaType, serve("127.0.0.1:7878", nil))
+	assert.Equal(t, http.StatusForbidden, serve("evil.example:7878", map[string]string{"Content-Type": "application/json"}))
+	assert.Equal(t, http.StatusForbidden, serve("127.0.0.1:7878", map[string]string{
+		"Content-Type": "application/json",
+		"Origin":       "https://evil.example",
+	}))
+	require.NoError(t, client.Refresh())
+	assert.Empty(t, client.GetProfiles())
+
+	assert.Equal(t, http.StatusCreated, serve("localhost:7878", map[string]string{
+		"Content-Type": "application/json; charset=utf-8",
+		"Origin":       "http://localhost:7878",
+	}))
+
+	server.allowHost("monitor.lan:7878")
+	req := httptest.NewRequest("GET", "http://monitor.lan:7878/api/profiles", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type recordingNotifier struct {
+	transitions []notify.Transition
+}
+
+func (r *recordingNotifier) Notify(t notify.Transition) error {
+	r.transitions = append(r.transitions, t)
+	return nil
+}
+
+func TestServer_Alerts(t *testing.T) {
+	var status int32 = http.StatusOK
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer target.Close()
+
+	server, client := newTestDaemon(t)
+	notifier := &recordingNotifier{}
+	alerts := notify.NewAlerter("", nil)
+	alerts.AddChannel(notify.ChannelHooks, notifier)
+	server.SetAlerter(alerts)
+
+	profile := models.Profile{Name: "api", BaseURL: target.URL}
+	require.NoError(t, client.AddProfile(profile))
+	server.scheduler.Unschedule("api")
+
+	_, err := client.Check("api")
+	require.NoError(t, err)
+	assert.Empty(t, notifier.transitions)
+
+	_, err = client.Acknowledge("api", time.Hour)
+	assert.ErrorContains(t, err, "no active alert")
+
+	atomic.StoreInt32(&status, http.StatusBadGateway)
+	_, err = client.Check("api")
+	require.NoError(t, err)
+	require.Len(t, notifier.transitions, 1)
+	assert.Equal(t, notify.StateDown, notifier.transitions[0].To)
+
+	state, err := client.Acknowledge("api", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, state.Firing())
+	assert.True(t, state.Acknowledged(time.Now()))
+
+	state, err = client.Silence("api", 30*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, state.Silenced(time.Now()))
+
+	state, err = client.Unsilence("api")
+	require.NoError(t, err)
+	assert.False(t, state.Silenced(time.Now()))
+	assert.False(t, state.Acknowledged(time.Now()))
+
+	atomic.StoreInt32(&status, http.StatusOK)
+	_, err = client.Check("api")
+	require.NoError(t, err)
+	require.Len(t, notifier.transitions, 2)
+	assert.Equal(t, notify.StateUp, notifier.transitions[1].To)
+
+	state, err = client.Alert("api")
+	require.NoError(t, err)
+	assert.False(t, state.Firing())
+
+	_, err = client.Alert("missing")
+	assert.ErrorContains(t, err, "profile not found")
+}
+
+func TestServer_ReloadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "api", "base_url": "http://localhost:1", "interval": 60}]`), 0644))
+	pm := models.NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+
+	history, err := NewHistory("", 0)
+	require.NoError(t, err)
+	server := NewServer(pm, history)
+	defer server.scheduler.Stop()
+
+	history.Add(models.PingRecord{Profile: "api", Timestamp: time.Now()})
+
+	changed, err := server.ReloadProfiles()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "web", "base_url": "http://localhost:2", "interval": 60}]`), 0644))
+	changed, err = server.ReloadProfiles()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	_, ok := server.findProfile("web")
+	assert.True(t, ok)
+	assert.Empty(t, history.Recent("api", 0))
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type Checker interface {
+	Check(profile Profile) PingResult
+}
+
+type CheckerFunc func(profile Profile) PingResult
+
+func (f CheckerFunc) Check(profile Profile) PingResult {
+	return f(profile)
+}
+
+func (ps *PingService) Register(profileType string, checker Checker) {
+	ps.checkers[profileType] = checker
+}
+
+func (ps *PingService) Ping(profile Profile) PingResult {
+	checker, ok := ps.checkers[profile.Kind()]
+	if !ok {
+		return PingResult{
+			Timestamp: time.Now(),
+			Error:     fmt.Errorf("unsupported profile type %q", profile.Kind()),
+		}
+	}
+	result := checker.Check(profile)
+	if window, ok := ps.InMaintenance(profile, result.Timestamp); ok {
+		result.Maintenance = window.Label()
+	}
+	return result
+}
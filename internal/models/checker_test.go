@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingService_CheckerRegistry(t *testing.T) {
+	ps := NewPingService()
+
+	result := ps.Ping(Profile{Name: "unknown", Type: "carrier-pigeon"})
+	assert.False(t, result.Success)
+	assert.EqualError(t, result.Error, `unsupported profile type "carrier-pigeon"`)
+	assert.False(t, result.Timestamp.IsZero())
+
+	ps.Register("carrier-pigeon", CheckerFunc(func(profile Profile) PingResult {
+		return PingResult{Timestamp: time.Now(), Success: true, Detail: "coo " + profile.Name}
+	}))
+
+	result = ps.Ping(Profile{Name: "bird", Type: "carrier-pigeon"})
+	assert.True(t, result.Success)
+	assert.Equal(t, "coo bird", result.Detail)
+}
+
+func TestProfile_KindAndTarget(t *testing.T) {
+	httpProfile := Profile{BaseURL: "https://api.example.com", Route: "/health"}
+	assert.Equal(t, ProfileTypeHTTP, httpProfile.Kind())
+	assert.Equal(t, "https://api.example.com/health", httpProfile.Target())
+
+	tcpProfile := Profile{Type: ProfileTypeTCP, BaseURL: "db.internal:5432"}
+	assert.Equal(t, ProfileTypeTCP, tcpProfile.Kind())
+	assert.Equal(t, "db.internal:5432", tcpProfile.Target())
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"* * * *", "must have 5 fields"},
+		{"60 * * * *", "invalid minute"},
+		{"* 24 * * *", "invalid hour"},
+		{"* * 0 * *", "invalid day of month"},
+		{"* * * foo *", "invalid month"},
+		{"* * * * 8", "invalid day of week"},
+		{"*/0 * * * *", "invalid minute step"},
+		{"* 10-2 * * *", "invalid hour range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	base := time.Date(2025, 6, 11, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 6, 11, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 6, 11, 10, 15, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2025, 6, 11, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2025, 6, 12, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * sun", time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 13 * fri", time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{"5,10 10 * * *", time.Date(2025, 6, 11, 10, 10, 0, 0, time.UTC)},
+		{"10/20 * * * *", time.Date(2025, 6, 11, 10, 10, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 6, 11, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cron.Next(base))
+		})
+	}
+}
+
+func TestCronSchedule_NextInLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	cron, err := ParseCron("0 9 * * *")
+	require.NoError(t, err)
+
+	next := cron.Next(time.Date(2025, 6, 11, 1, 0, 0, 0, time.UTC).In(tokyo))
+	assert.Equal(t, time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC), next.UTC())
+}
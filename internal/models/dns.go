@@ -0,0 +1,225 @@
+package models
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const ProfileTypeDNS = "dns"
+
+var dnsRecordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"TXT":   dnsmessage.TypeTXT,
+	"MX":    dnsmessage.TypeMX,
+	"SRV":   dnsmessage.TypeSRV,
+}
+
+type DNSConfig struct {
+	Resolver       string   `json:"resolver,omitempty"`
+	RecordType     string   `json:"record_type,omitempty"`
+	Expect         []string `json:"expect,omitempty"`
+	ExpectCount    int      `json:"expect_count,omitempty"`
+	MinAnswers     int      `json:"min_answers,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+type DNSChecker struct{}
+
+func dnsResolverAddress(resolver string) string {
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		return net.JoinHostPort(resolver, "53")
+	}
+	return resolver
+}
+
+func (c *DNSChecker) Check(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	config := DNSConfig{}
+	if profile.DNS != nil {
+		config = *profile.DNS
+	}
+
+	recordType := strings.ToUpper(config.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dnsRecordTypes[recordType]
+	if !ok {
+		result.Error = fmt.Errorf("unsupported record type %q", config.RecordType)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	if config.Resolver == "" {
+		result.Error = fmt.Errorf("dns resolver is required")
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(config.TimeoutSeconds))
+	defer cancel()
+
+	answers, err := LookupDNS(ctx, dnsResolverAddress(config.Resolver), strings.TrimPrefix(profile.BaseURL, "dns://"), qtype)
+	result.Duration = time.Since(start)
+	result.Detail = strings.Join(answers, ", ")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Error = checkDNSAnswers(answers, config)
+	result.Success = result.Error == nil
+	return result
+}
+
+func normalizeDNSValue(value string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(value), "."))
+}
+
+func checkDNSAnswers(answers []string, config DNSConfig) error {
+	if config.ExpectCount > 0 && len(answers) != config.ExpectCount {
+		return fmt.Errorf("expected %d answers, got %d", config.ExpectCount, len(answers))
+	}
+
+	minAnswers := config.MinAnswers
+	if minAnswers == 0 && config.ExpectCount == 0 {
+		minAnswers = 1
+	}
+	if len(answers) < minAnswers {
+		return fmt.Errorf("expected at least %d answers, got %d", minAnswers, len(answers))
+	}
+
+	normalized := make([]string, len(answers))
+	for i, answer := range answers {
+		normalized[i] = normalizeDNSValue(answer)
+	}
+	for _, expected := range config.Expect {
+		if !slices.Contains(normalized, normalizeDNSValue(expected)) {
+			return fmt.Errorf("expected answer %q not found", expected)
+		}
+	}
+
+	return nil
+}
+
+func LookupDNS(ctx context.Context, resolver, name string, qtype dnsmessage.Type) ([]string, error) {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(rand.UintN(1 << 16)), RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: qname, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := exchangeDNS(ctx, "udp", resolver, packed)
+	if err == nil && resp.Truncated {
+		resp, err = exchangeDNS(ctx, "tcp", resolver, packed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.ID != query.ID {
+		return nil, fmt.Errorf("dns response id mismatch")
+	}
+	if resp.RCode != dnsmessage.RCodeSuccess {
+		return nil, fmt.Errorf("dns query failed: %s", strings.TrimPrefix(resp.RCode.String(), "RCode"))
+	}
+
+	var answers []string
+	for _, answer := range resp.Answers {
+		if answer.Header.Type != qtype {
+			continue
+		}
+		answers = append(answers, formatDNSResource(answer.Body))
+	}
+	return answers, nil
+}
+
+func exchangeDNS(ctx context.Context, network, resolver string, query []byte) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, resolver)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	buf := make([]byte, 65535)
+	var n int
+	if network == "tcp" {
+		framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+		if _, err := conn.Write(append(framed, query...)); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return nil, err
+		}
+		n = int(binary.BigEndian.Uint16(buf[:2]))
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		if n, err = conn.Read(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf[:n]); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func formatDNSResource(body dnsmessage.ResourceBody) string {
+	switch r := body.(type) {
+	case *dnsmessage.AResource:
+		return netip.AddrFrom4(r.A).String()
+	case *dnsmessage.AAAAResource:
+		return netip.AddrFrom16(r.AAAA).String()
+	case *dnsmessage.CNAMEResource:
+		return r.CNAME.String()
+	case *dnsmessage.TXTResource:
+		return strings.Join(r.TXT, "")
+	case *dnsmessage.MXResource:
+		return fmt.Sprintf("%d %s", r.Pref, r.MX.String())
+	case *dnsmessage.SRVResource:
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Target.String())
+	default:
+		return body.GoString()
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func newStubDNSServer(t *testing.T, records map[dnsmessage.Type][]dnsmessage.ResourceBody) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+				continue
+			}
+			question := query.Questions[0]
+
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+			}
+			if question.Name.String() != "api.route-keeper.test." {
+				resp.RCode = dnsmessage.RCodeNameError
+			}
+			for _, body := range records[question.Type] {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60},
+					Body:   body,
+				})
+			}
+
+			packed, err := resp.Pack()
+			if err == nil {
+				conn.WriteTo(packed, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNSChecker(t *testing.T) {
+	resolver := newStubDNSServer(t, map[dnsmessage.Type][]dnsmessage.ResourceBody{
+		dnsmessage.TypeA: {
+			&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+			&dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}},
+		},
+		dnsmessage.TypeCNAME: {&dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("lb.route-keeper.test.")}},
+		dnsmessage.TypeTXT:   {&dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}}},
+		dnsmessage.TypeMX:    {&dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx.route-keeper.test.")}},
+		dnsmessage.TypeSRV:   {&dnsmessage.SRVResource{Priority: 1, Weight: 5, Port: 443, Target: dnsmessage.MustNewName("api.route-keeper.test.")}},
+	})
+
+	ps := NewPingService()
+	dnsProfile := func(config DNSConfig) Profile {
+		config.Resolver = resolver
+		return Profile{Name: "dns", Type: ProfileTypeDNS, BaseURL: "api.route-keeper.test", DNS: &config}
+	}
+
+	result := ps.Ping(dnsProfile(DNSConfig{Expect: []string{"10.0.0.2"}, ExpectCount: 2}))
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, "10.0.0.1, 10.0.0.2", result.Detail)
+
+	result = ps.Ping(dnsProfile(DNSConfig{Expect: []string{"10.0.0.3"}}))
+	assert.False(t, result.Success)
+	assert.EqualError(t, result.Error, `expected answer "10.0.0.3" not found`)
+
+	result = ps.Ping(dnsProfile(DNSConfig{ExpectCount: 3}))
+	assert.EqualError(t, result.Error, "expected 3 answers, got 2")
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "cname", Expect: []string{"LB.route-keeper.test"}}))
+	assert.True(t, result.Success)
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "TXT", Expect: []string{"v=spf1 -all"}}))
+	assert.True(t, result.Success)
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "MX"}))
+	assert.Equal(t, "10 mx.route-keeper.test.", result.Detail)
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "SRV"}))
+	assert.Equal(t, "1 5 443 api.route-keeper.test.", result.Detail)
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "AAAA"}))
+	assert.False(t, result.Success)
+	assert.EqualError(t, result.Error, "expected at least 1 answers, got 0")
+
+	result = ps.Ping(dnsProfile(DNSConfig{RecordType: "PTR"}))
+	assert.EqualError(t, result.Error, `unsupported record type "PTR"`)
+
+	profile := dnsProfile(DNSConfig{})
+	profile.BaseURL = "missing.route-keeper.test"
+	result = ps.Ping(profile)
+	assert.EqualError(t, result.Error, "dns query failed: NameError")
+
+	result = ps.Ping(Profile{Name: "no resolver", Type: ProfileTypeDNS, BaseURL: "api.route-keeper.test"})
+	assert.EqualError(t, result.Error, "dns resolver is required")
+}
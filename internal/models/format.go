@@ -0,0 +1,252 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+func FormatForPath(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported profiles file extension %q (use .json, .yaml, .yml or .toml)", ext)
+	}
+}
+
+type profileDocument struct {
+	Version  int       `json:"version"`
+	Include  []string  `json:"include,omitempty"`
+	Profiles []Profile `json:"profiles"`
+}
+
+func decodeDocument(format string, data []byte) (profileDocument, int, error) {
+	var doc profileDocument
+	var raw any
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return doc, 0, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return doc, 0, err
+		}
+	case FormatTOML:
+		var table map[string]any
+		if err := toml.Unmarshal(data, &table); err != nil {
+			return doc, 0, err
+		}
+		raw = table
+	default:
+		return doc, 0, fmt.Errorf("unsupported profiles format %q", format)
+	}
+
+	if raw == nil {
+		doc.Version = ConfigVersion
+		return doc, ConfigVersion, nil
+	}
+
+	migrated, from, err := migrateDocument(raw)
+	if err != nil {
+		return doc, 0, err
+	}
+
+	data, err = json.Marshal(migrated)
+	if err != nil {
+		return doc, 0, err
+	}
+	err = json.Unmarshal(data, &doc)
+	return doc, from, err
+}
+
+func encodeDocument(format string, doc profileDocument, previous []byte) ([]byte, error) {
+	doc.Version = ConfigVersion
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		return data, nil
+	case FormatYAML:
+		return encodeYAML(data, previous)
+	case FormatTOML:
+		return encodeTOML(data)
+	default:
+		return nil, fmt.Errorf("unsupported profiles format %q", format)
+	}
+}
+
+func encodeYAML(data, previous []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	tidyYAML(&doc)
+
+	if len(previous) > 0 {
+		var old yaml.Node
+		if yaml.Unmarshal(previous, &old) == nil {
+			copyComments(&old, &doc)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func tidyYAML(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.MappingNode {
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i+1].Tag == "!!null" {
+				continue
+			}
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+		node.Content = content
+	}
+	for _, child := range node.Content {
+		tidyYAML(child)
+	}
+}
+
+func copyComments(from, to *yaml.Node) {
+	if from.Kind != to.Kind {
+		return
+	}
+	to.HeadComment = from.HeadComment
+	to.LineComment = from.LineComment
+	to.FootComment = from.FootComment
+
+	switch to.Kind {
+	case yaml.DocumentNode:
+		if len(from.Content) == 0 || len(to.Content) == 0 {
+			return
+		}
+		oldRoot, newRoot := from.Content[0], to.Content[0]
+		if oldRoot.Kind == yaml.SequenceNode && newRoot.Kind == yaml.MappingNode {
+			if i := mappingIndex(newRoot, "profiles"); i >= 0 {
+				newRoot.HeadComment = oldRoot.HeadComment
+				oldRoot.HeadComment = ""
+				copyComments(oldRoot, newRoot.Content[i+1])
+			}
+			return
+		}
+		copyComments(oldRoot, newRoot)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(to.Content); i += 2 {
+			if j := mappingIndex(from, to.Content[i].Value); j >= 0 {
+				copyComments(from.Content[j], to.Content[i])
+				copyComments(from.Content[j+1], to.Content[i+1])
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range to.Content {
+			if match := matchingItem(from, item, i); match != nil {
+				copyComments(match, item)
+			}
+		}
+	}
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func matchingItem(seq, item *yaml.Node, index int) *yaml.Node {
+	if item.Kind == yaml.MappingNode {
+		if k := mappingIndex(item, "name"); k >= 0 {
+			name := item.Content[k+1].Value
+			for _, candidate := range seq.Content {
+				if candidate.Kind != yaml.MappingNode {
+					continue
+				}
+				if j := mappingIndex(candidate, "name"); j >= 0 && candidate.Content[j+1].Value == name {
+					return candidate
+				}
+			}
+			return nil
+		}
+	}
+	if index < len(seq.Content) {
+		return seq.Content[index]
+	}
+	return nil
+}
+
+func encodeTOML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return toml.Marshal(tomlValue(doc))
+}
+
+func tomlValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, child := range v {
+			if child == nil {
+				delete(v, key)
+				continue
+			}
+			if m, ok := child.(map[string]any); ok && len(m) == 0 {
+				delete(v, key)
+				continue
+			}
+			v[key] = tomlValue(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = tomlValue(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
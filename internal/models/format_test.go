@@ -0,0 +1,117 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatForPath(t *testing.T) {
+	tests := map[string]string{
+		"profiles.json":       FormatJSON,
+		"route-keeper.yaml":   FormatYAML,
+		"route-keeper.YML":    FormatYAML,
+		"./ops/profiles.toml": FormatTOML,
+	}
+	for path, want := range tests {
+		format, err := FormatForPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+
+	_, err := FormatForPath("profiles.ini")
+	assert.ErrorContains(t, err, `".ini"`)
+}
+
+func TestProfilesManager_YAMLPreservesComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route-keeper.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# Project monitors
+- name: api # primary
+  base_url: https://api.example.com
+  route: /health
+  # checked every minute
+  interval: 1
+
+# Database
+- name: db
+  type: tcp
+  base_url: "db:5432"
+  interval: 5
+`), 0644))
+
+	pm := NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+	require.Len(t, pm.GetProfiles(), 2)
+	assert.Equal(t, "https://api.example.com", pm.GetProfiles()[0].BaseURL)
+	assert.Equal(t, ProfileTypeTCP, pm.GetProfiles()[1].Type)
+
+	api := pm.GetProfiles()[0]
+	api.Interval = 2
+	api.Headers = map[string]string{"Accept": "application/json"}
+	require.NoError(t, pm.AddProfile(api))
+	require.NoError(t, pm.AddProfile(Profile{Name: "web", BaseURL: "https://example.com", Interval: 10}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# Project monitors")
+	assert.Contains(t, content, "name: api # primary")
+	assert.Contains(t, content, "    # checked every minute\n    interval: 2")
+	assert.Contains(t, content, "# Database")
+	assert.Contains(t, content, "base_url: db:5432")
+	assert.Contains(t, content, "Accept: application/json")
+	assert.NotContains(t, content, "null")
+	assert.NotContains(t, content, "{")
+
+	reloaded := NewProfilesManagerForFile(path)
+	require.NoError(t, reloaded.LoadProfiles())
+	assert.Equal(t, pm.GetProfiles(), reloaded.GetProfiles())
+}
+
+func TestProfilesManager_TOMLRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+[[profiles]]
+name = "api"
+base_url = "https://api.example.com"
+route = "/health"
+interval = 1
+
+[profiles.headers]
+Accept = "application/json"
+
+[profiles.schedule]
+cron = "*/5 9-17 * * 1-5"
+jitter_seconds = 30
+`), 0644))
+
+	pm := NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+	require.Len(t, pm.GetProfiles(), 1)
+	api := pm.GetProfiles()[0]
+	assert.Equal(t, "application/json", api.Headers["Accept"])
+	require.NotNil(t, api.Schedule)
+	assert.Equal(t, 30, api.Schedule.JitterSeconds)
+
+	require.NoError(t, pm.AddProfile(Profile{Name: "dns", Type: ProfileTypeDNS, BaseURL: "example.com", Interval: 5}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "[[profiles]]")
+	assert.Contains(t, string(data), "interval = 5\n")
+
+	reloaded := NewProfilesManagerForFile(path)
+	require.NoError(t, reloaded.LoadProfiles())
+	require.Len(t, reloaded.GetProfiles(), 2)
+	assert.Equal(t, api, reloaded.GetProfiles()[0])
+	assert.Equal(t, "dns", reloaded.GetProfiles()[1].Name)
+}
+
+func TestProfilesManager_UnsupportedExtension(t *testing.T) {
+	pm := NewProfilesManagerForFile(filepath.Join(t.TempDir(), "profiles.ini"))
+	assert.Error(t, pm.LoadProfiles())
+	assert.Error(t, pm.SaveProfiles())
+}
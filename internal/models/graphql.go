@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const ProfileTypeGraphQL = "graphql"
+
+type GraphQLConfig struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operation_name,omitempty"`
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+type graphQLResponse struct {
+	Errors []graphQLError `json:"errors"`
+}
+
+func (e graphQLError) String() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	segments := make([]string, len(e.Path))
+	for i, segment := range e.Path {
+		segments[i] = jsonValueString(segment)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(segments, "."), e.Message)
+}
+
+func inspectGraphQL(body []byte, result *PingResult) {
+	var resp graphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		if result.Success {
+			result.Error = fmt.Errorf("invalid GraphQL response: %w", err)
+			result.Success = false
+		}
+		return
+	}
+
+	for _, gqlErr := range resp.Errors {
+		result.GraphQLErrors = append(result.GraphQLErrors, gqlErr.String())
+	}
+	if len(result.GraphQLErrors) > 0 {
+		result.Success = false
+	}
+}
+
+func (ps *PingService) pingGraphQL(profile Profile) PingResult {
+	config := GraphQLConfig{}
+	if profile.GraphQL != nil {
+		config = *profile.GraphQL
+	}
+
+	if strings.TrimSpace(config.Query) == "" {
+		return PingResult{Timestamp: time.Now(), Error: fmt.Errorf("graphql query is required")}
+	}
+
+	body, err := json.Marshal(graphQLRequest{
+		Query:         config.Query,
+		Variables:     config.Variables,
+		OperationName: config.OperationName,
+	})
+	if err != nil {
+		return PingResult{Timestamp: time.Now(), Error: err}
+	}
+
+	return ps.doRequest(profile, "POST", body, inspectGraphQL)
+}
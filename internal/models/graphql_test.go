@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGraphQLServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		variables, _ := req["variables"].(map[string]any)
+		switch {
+		case req["operationName"] != "Order":
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": []any{map[string]any{"message": "unknown operation"}},
+			})
+		case variables["id"] == "missing":
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"order": nil},
+				"errors": []any{
+					map[string]any{"message": "order not found", "path": []any{"order", 0, "id"}},
+					map[string]any{"message": "rate limited"},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"order": map[string]any{"id": variables["id"]}},
+			})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPingService_GraphQL(t *testing.T) {
+	server := newGraphQLServer(t)
+	ps := NewPingService()
+
+	profile := Profile{
+		Name:    "orders",
+		Type:    ProfileTypeGraphQL,
+		BaseURL: server.URL,
+		Route:   "/graphql",
+		GraphQL: &GraphQLConfig{
+			Query:         "query Order($id: ID!) { order(id: $id) { id } }",
+			Variables:     map[string]any{"id": "42"},
+			OperationName: "Order",
+		},
+	}
+
+	result := ps.Ping(profile)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.GraphQLErrors)
+
+	profile.GraphQL.Variables["id"] = "missing"
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, []string{"order.0.id: order not found", "rate limited"}, result.GraphQLErrors)
+
+	profile.GraphQL.OperationName = "Other"
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+	assert.Equal(t, []string{"unknown operation"}, result.GraphQLErrors)
+
+	result = ps.Ping(Profile{Name: "empty", Type: ProfileTypeGraphQL, BaseURL: server.URL})
+	assert.EqualError(t, result.Error, "graphql query is required")
+}
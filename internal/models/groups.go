@@ -0,0 +1,76 @@
+package models
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+const GroupSeparator = "/"
+
+func GroupPath(group string) []string {
+	var path []string
+	for _, folder := range strings.Split(group, GroupSeparator) {
+		if folder = strings.TrimSpace(folder); folder != "" {
+			path = append(path, folder)
+		}
+	}
+	return path
+}
+
+func (p *Profile) InGroup(group string) bool {
+	want := GroupPath(group)
+	have := GroupPath(p.Group)
+	return len(want) > 0 && len(have) >= len(want) && slices.Equal(have[:len(want)], want)
+}
+
+func (p *Profile) HasTag(tag string) bool {
+	return slices.Contains(p.Tags, tag)
+}
+
+type ProfileSelector struct {
+	Group string
+	Tag   string
+}
+
+func (s ProfileSelector) Match(profile Profile) bool {
+	switch {
+	case s.Group != "":
+		return profile.InGroup(s.Group)
+	case s.Tag != "":
+		return profile.HasTag(s.Tag)
+	}
+	return false
+}
+
+func (s ProfileSelector) String() string {
+	if s.Tag != "" {
+		return "#" + s.Tag
+	}
+	return strings.Join(GroupPath(s.Group), GroupSeparator)
+}
+
+func SelectProfiles(profiles []Profile, selector ProfileSelector) []Profile {
+	var selected []Profile
+	for _, profile := range profiles {
+		if selector.Match(profile) {
+			selected = append(selected, profile)
+		}
+	}
+	return selected
+}
+
+func ProfileTags(profiles []Profile) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, profile := range profiles {
+		for _, tag := range profile.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
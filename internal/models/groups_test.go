@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileSelector(t *testing.T) {
+	profiles := []Profile{
+		{Name: "checkout", Group: "payments/eu", Tags: []string{"prod", "critical"}},
+		{Name: "refunds", Group: " payments / us "},
+		{Name: "pay", Group: "pay"},
+		{Name: "solo", Tags: []string{"prod"}},
+	}
+
+	names := func(profiles []Profile) []string {
+		var names []string
+		for _, profile := range profiles {
+			names = append(names, profile.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"payments", "us"}, GroupPath(" payments / us "))
+	assert.Equal(t, []string{"checkout", "refunds"}, names(SelectProfiles(profiles, ProfileSelector{Group: "payments"})))
+	assert.Equal(t, []string{"checkout"}, names(SelectProfiles(profiles, ProfileSelector{Group: "payments/eu/"})))
+	assert.Equal(t, []string{"checkout", "solo"}, names(SelectProfiles(profiles, ProfileSelector{Tag: "prod"})))
+	assert.Empty(t, SelectProfiles(profiles, ProfileSelector{}))
+
+	assert.Equal(t, []string{"critical", "prod"}, ProfileTags(profiles))
+	assert.Equal(t, "payments/eu", ProfileSelector{Group: "payments/ eu"}.String())
+	assert.Equal(t, "#prod", ProfileSelector{Tag: "prod"}.String())
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const ProfileTypeGRPC = "grpc"
+
+type GRPCConfig struct {
+	Service            string            `json:"service,omitempty"`
+	TLS                bool              `json:"tls,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+	ServerName         string            `json:"server_name,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	TimeoutSeconds     int               `json:"timeout_seconds,omitempty"`
+}
+
+type GRPCChecker struct{}
+
+func grpcTarget(baseURL string) (string, bool) {
+	switch {
+	case strings.HasPrefix(baseURL, "grpcs://"):
+		return strings.TrimSuffix(strings.TrimPrefix(baseURL, "grpcs://"), "/"), true
+	case strings.HasPrefix(baseURL, "grpc://"):
+		return strings.TrimSuffix(strings.TrimPrefix(baseURL, "grpc://"), "/"), false
+	default:
+		return strings.TrimSuffix(baseURL, "/"), false
+	}
+}
+
+func (c *GRPCChecker) Check(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	config := GRPCConfig{}
+	if profile.GRPC != nil {
+		config = *profile.GRPC
+	}
+
+	target, useTLS := grpcTarget(profile.BaseURL)
+	useTLS = useTLS || config.TLS
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName:         config.ServerName,
+			InsecureSkipVerify: config.InsecureSkipVerify,
+		})
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(config.TimeoutSeconds))
+	defer cancel()
+
+	if len(config.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(config.Metadata))
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: config.Service})
+	result.Duration = time.Since(start)
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			result.Detail = st.Code().String()
+			result.Error = fmt.Errorf("%s: %s", st.Code(), st.Message())
+		} else {
+			result.Error = err
+		}
+		return result
+	}
+
+	result.Detail = resp.GetStatus().String()
+	result.Success = resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+	return result
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newGRPCHealthServer(t *testing.T) (string, *health.Server) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	requireToken := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if req.(*healthpb.HealthCheckRequest).GetService() == "secured" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if len(md.Get("x-api-key")) == 0 || md.Get("x-api-key")[0] != "k3y" {
+				return nil, status.Error(codes.Unauthenticated, "missing api key")
+			}
+		}
+		return handler(ctx, req)
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(requireToken))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), healthServer
+}
+
+func TestGRPCChecker(t *testing.T) {
+	addr, healthServer := newGRPCHealthServer(t)
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("secured", healthpb.HealthCheckResponse_SERVING)
+
+	ps := NewPingService()
+
+	result := ps.Ping(Profile{Name: "overall", Type: ProfileTypeGRPC, BaseURL: "grpc://" + addr})
+	assert.True(t, result.Success)
+	assert.Equal(t, "SERVING", result.Detail)
+	assert.NoError(t, result.Error)
+
+	result = ps.Ping(Profile{Name: "orders", Type: ProfileTypeGRPC, BaseURL: addr, GRPC: &GRPCConfig{Service: "orders"}})
+	assert.False(t, result.Success)
+	assert.Equal(t, "NOT_SERVING", result.Detail)
+
+	result = ps.Ping(Profile{Name: "missing", Type: ProfileTypeGRPC, BaseURL: addr, GRPC: &GRPCConfig{Service: "missing"}})
+	assert.False(t, result.Success)
+	assert.Equal(t, "NotFound", result.Detail)
+	assert.Error(t, result.Error)
+
+	result = ps.Ping(Profile{Name: "secured", Type: ProfileTypeGRPC, BaseURL: addr, GRPC: &GRPCConfig{Service: "secured"}})
+	assert.False(t, result.Success)
+	assert.Equal(t, "Unauthenticated", result.Detail)
+
+	result = ps.Ping(Profile{Name: "secured", Type: ProfileTypeGRPC, BaseURL: addr, GRPC: &GRPCConfig{
+		Service:  "secured",
+		Metadata: map[string]string{"x-api-key": "k3y"},
+	}})
+	assert.True(t, result.Success)
+}
+
+func TestGRPCTarget(t *testing.T) {
+	target, useTLS := grpcTarget("grpcs://api.example.com:443/")
+	assert.Equal(t, "api.example.com:443", target)
+	assert.True(t, useTLS)
+
+	target, useTLS = grpcTarget("localhost:50051")
+	assert.Equal(t, "localhost:50051", target)
+	assert.False(t, useTLS)
+}
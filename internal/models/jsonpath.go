@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func splitJSONPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func LookupJSONPath(doc any, path string) (any, bool) {
+	current := doc
+	for _, segment := range splitJSONPath(path) {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func jsonValueString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func DeleteJSONPath(doc any, path string) {
+	segments := splitJSONPath(path)
+	if len(segments) == 0 {
+		return
+	}
+	deleteJSONSegments(doc, segments)
+}
+
+func deleteJSONSegments(node any, segments []string) {
+	segment, rest := segments[0], segments[1:]
+
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			if segment == "*" {
+				for k := range n {
+					delete(n, k)
+				}
+			} else {
+				delete(n, segment)
+			}
+			return
+		}
+		if segment == "*" {
+			for _, child := range n {
+				deleteJSONSegments(child, rest)
+			}
+		} else if child, ok := n[segment]; ok {
+			deleteJSONSegments(child, rest)
+		}
+	case []any:
+		if len(rest) == 0 {
+			return
+		}
+		if segment == "*" {
+			for _, child := range n {
+				deleteJSONSegments(child, rest)
+			}
+		} else if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(n) {
+			deleteJSONSegments(n[index], rest)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupJSONPath(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(`{"data":{"items":[{"id":1},{"id":2,"tags":["a"]}],"ok":true}}`), &doc))
+
+	tests := []struct {
+		path     string
+		expected string
+		found    bool
+	}{
+		{"data.ok", "true", true},
+		{"$.data.items[1].id", "2", true},
+		{"data.items.1.tags", `["a"]`, true},
+		{"data.items[5]", "", false},
+		{"data.missing", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, ok := LookupJSONPath(doc, tt.path)
+			assert.Equal(t, tt.found, ok)
+			if ok {
+				assert.Equal(t, tt.expected, jsonValueString(value))
+			}
+		})
+	}
+}
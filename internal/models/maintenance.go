@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+type MaintenanceWindow struct {
+	Name string `json:"name,omitempty"`
+	TimeWindow
+	Profiles []string `json:"profiles,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func (w MaintenanceWindow) Label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return "maintenance"
+}
+
+func (w MaintenanceWindow) Validate() error {
+	if err := w.TimeWindow.Validate(); err != nil {
+		return fmt.Errorf("maintenance window %q: %w", w.Label(), err)
+	}
+	return nil
+}
+
+func (w MaintenanceWindow) AppliesTo(profile Profile) bool {
+	if len(w.Profiles) == 0 && len(w.Groups) == 0 && len(w.Tags) == 0 {
+		return true
+	}
+	for _, pattern := range w.Profiles {
+		if ok, _ := path.Match(pattern, profile.Name); ok {
+			return true
+		}
+	}
+	for _, group := range w.Groups {
+		if profile.InGroup(group) {
+			return true
+		}
+	}
+	for _, tag := range w.Tags {
+		if profile.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func ActiveMaintenance(profile Profile, global []MaintenanceWindow, now time.Time) (MaintenanceWindow, bool) {
+	for _, w := range profile.Maintenance {
+		if w.Active(now) {
+			return w, true
+		}
+	}
+	for _, w := range global {
+		if w.AppliesTo(profile) && w.Active(now) {
+			return w, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
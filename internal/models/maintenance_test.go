@@ -0,0 +1,110 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr string
+	}{
+		{"one-off", MaintenanceWindow{TimeWindow: TimeWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00"}}, ""},
+		{"recurring", MaintenanceWindow{TimeWindow: TimeWindow{Start: "23:30", End: "00:30", Days: []string{"Sun"}, TimeZone: "Europe/Berlin"}}, ""},
+		{"bad zone", MaintenanceWindow{TimeWindow: TimeWindow{Start: "01:00", End: "02:00", TimeZone: "Mars/Base"}}, "unknown time zone"},
+		{"bad start", MaintenanceWindow{Name: "deploy", TimeWindow: TimeWindow{Start: "25:00", End: "02:00"}}, `"deploy": invalid start`},
+		{"end before start", MaintenanceWindow{TimeWindow: TimeWindow{Start: "2025-03-02 02:00", End: "2025-03-01 22:00"}}, "end must be after start"},
+		{"days on one-off", MaintenanceWindow{TimeWindow: TimeWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00", Days: []string{"sat"}}}, "days only apply"},
+		{"bad day", MaintenanceWindow{TimeWindow: TimeWindow{Start: "01:00", End: "02:00", Days: []string{"someday"}}}, "invalid day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_Active(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	oneOff := MaintenanceWindow{TimeWindow: TimeWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00", TimeZone: "Europe/Berlin"}}
+	assert.False(t, oneOff.Active(time.Date(2025, 3, 1, 21, 59, 0, 0, berlin)))
+	assert.True(t, oneOff.Active(time.Date(2025, 3, 1, 22, 0, 0, 0, berlin)))
+	assert.True(t, oneOff.Active(time.Date(2025, 3, 2, 0, 30, 0, 0, time.UTC)))
+	assert.False(t, oneOff.Active(time.Date(2025, 3, 2, 2, 0, 0, 0, berlin)))
+
+	overnight := MaintenanceWindow{TimeWindow: TimeWindow{Start: "23:30", End: "00:30", Days: []string{"sun"}, TimeZone: "Europe/Berlin"}}
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, berlin)
+	assert.True(t, overnight.Active(sunday.Add(23*time.Hour+45*time.Minute)))
+	assert.True(t, overnight.Active(sunday.Add(24*time.Hour+15*time.Minute)), "window started on sunday continues past midnight")
+	assert.False(t, overnight.Active(sunday.Add(15*time.Minute)), "window started on saturday is not scheduled")
+	assert.False(t, overnight.Active(sunday.Add(24*time.Hour+30*time.Minute)))
+
+	daily := MaintenanceWindow{TimeWindow: TimeWindow{Start: "02:00", End: "03:00", TimeZone: "UTC"}}
+	assert.True(t, daily.Active(time.Date(2025, 6, 11, 2, 30, 0, 0, time.UTC)))
+	assert.True(t, daily.Active(time.Date(2025, 6, 11, 4, 30, 0, 0, berlin)))
+	assert.False(t, daily.Active(time.Date(2025, 6, 11, 3, 0, 0, 0, time.UTC)))
+
+	assert.False(t, MaintenanceWindow{TimeWindow: TimeWindow{Start: "bad", End: "02:00"}}.Active(time.Now()))
+}
+
+func TestActiveMaintenance(t *testing.T) {
+	now := time.Date(2025, 6, 11, 2, 30, 0, 0, time.UTC)
+	nightly := MaintenanceWindow{Name: "nightly", TimeWindow: TimeWindow{Start: "02:00", End: "03:00", TimeZone: "UTC"}, Profiles: []string{"billing-*"}}
+
+	_, ok := ActiveMaintenance(Profile{Name: "search"}, []MaintenanceWindow{nightly}, now)
+	assert.False(t, ok)
+
+	window, ok := ActiveMaintenance(Profile{Name: "billing-api"}, []MaintenanceWindow{nightly}, now)
+	assert.True(t, ok)
+	assert.Equal(t, "nightly", window.Label())
+
+	own := Profile{Name: "search", Maintenance: []MaintenanceWindow{{TimeWindow: TimeWindow{Start: "02:15", End: "02:45", TimeZone: "UTC"}}}}
+	window, ok = ActiveMaintenance(own, nil, now)
+	assert.True(t, ok)
+	assert.Equal(t, "maintenance", window.Label())
+}
+
+func TestMaintenanceWindow_AppliesTo(t *testing.T) {
+	window := MaintenanceWindow{Profiles: []string{"billing-*"}, Groups: []string{"payments"}, Tags: []string{"batch"}}
+
+	assert.True(t, window.AppliesTo(Profile{Name: "billing-api"}))
+	assert.True(t, window.AppliesTo(Profile{Name: "checkout", Group: "payments/eu"}))
+	assert.True(t, window.AppliesTo(Profile{Name: "export", Tags: []string{"nightly", "batch"}}))
+	assert.False(t, window.AppliesTo(Profile{Name: "search", Group: "payments-legacy", Tags: []string{"prod"}}))
+	assert.True(t, MaintenanceWindow{}.AppliesTo(Profile{Name: "search"}))
+}
+
+func TestPingService_MarksMaintenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ps := NewPingService()
+	profile := Profile{Name: "api", BaseURL: server.URL}
+
+	result := ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Empty(t, result.Maintenance)
+
+	ps.SetMaintenanceWindows([]MaintenanceWindow{{Name: "upgrade", TimeWindow: TimeWindow{Start: "00:00", End: "00:00"}}})
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Equal(t, "upgrade", result.Maintenance)
+	assert.Equal(t, "upgrade", NewPingRecord("api", result).Result().Maintenance)
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+)
+
+const ConfigVersion = 1
+
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc any) (any, error)
+}
+
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "wrap the profile list in a versioned envelope",
+		Apply: func(doc any) (any, error) {
+			if list, ok := doc.([]any); ok {
+				return map[string]any{"profiles": list}, nil
+			}
+			return doc, nil
+		},
+	},
+}
+
+func migrationSteps(from int) []string {
+	var steps []string
+	for _, migration := range migrations {
+		if migration.From >= from {
+			steps = append(steps, migration.Description)
+		}
+	}
+	return steps
+}
+
+func documentVersion(raw any) (int, error) {
+	table, ok := raw.(map[string]any)
+	if !ok {
+		return 0, nil
+	}
+
+	switch v := table["version"].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("version must be a whole number")
+}
+
+func migrateDocument(raw any) (map[string]any, int, error) {
+	from, err := documentVersion(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > ConfigVersion {
+		return nil, 0, fmt.Errorf("version %d is newer than this route-keeper supports (%d); please upgrade", from, ConfigVersion)
+	}
+
+	for _, migration := range migrations {
+		if migration.From < from {
+			continue
+		}
+		if raw, err = migration.Apply(raw); err != nil {
+			return nil, 0, fmt.Errorf("migrate from version %d: %w", migration.From, err)
+		}
+	}
+
+	doc, ok := raw.(map[string]any)
+	if !ok {
+		return nil, 0, fmt.Errorf("expected a list of profiles or an object with a profiles key")
+	}
+	doc["version"] = ConfigVersion
+	return doc, from, nil
+}
+
+type FileMigration struct {
+	Path   string
+	From   int
+	To     int
+	Steps  []string
+	Before []byte
+	After  []byte
+}
+
+func (pm *ProfilesManager) Migrate(dryRun bool) ([]FileMigration, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	lock, err := acquireLock(pm.lockPath(), !dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	files, err := loadProfileFiles(pm.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []FileMigration
+	for _, file := range files {
+		if file.version >= ConfigVersion {
+			continue
+		}
+
+		before, err := os.ReadFile(file.path)
+		if err != nil {
+			return results, err
+		}
+		after, err := file.render(before)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", file.path, err)
+		}
+		results = append(results, FileMigration{
+			Path:   file.path,
+			From:   file.version,
+			To:     ConfigVersion,
+			Steps:  migrationSteps(file.version),
+			Before: before,
+			After:  after,
+		})
+
+		if dryRun {
+			continue
+		}
+		if err := rotateBackups(file.path, before, profileBackups); err != nil {
+			return results, fmt.Errorf("backup %s: %w", file.path, err)
+		}
+		if err := WriteFileAtomic(file.path, after, 0644); err != nil {
+			return results, err
+		}
+		file.sum = sha256.Sum256(after)
+		file.version = ConfigVersion
+	}
+
+	if !dryRun {
+		pm.adopt(files)
+	}
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	doc, from, err := migrateDocument([]any{map[string]any{"name": "api"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, from)
+	assert.Equal(t, ConfigVersion, doc["version"])
+	assert.Len(t, doc["profiles"], 1)
+
+	_, from, err = migrateDocument(map[string]any{"version": float64(ConfigVersion), "profiles": []any{}})
+	require.NoError(t, err)
+	assert.Equal(t, ConfigVersion, from)
+
+	_, _, err = migrateDocument(map[string]any{"version": float64(ConfigVersion + 1)})
+	assert.ErrorContains(t, err, "newer than this route-keeper supports")
+
+	_, _, err = migrateDocument(map[string]any{"version": "one"})
+	assert.ErrorContains(t, err, "version must be a whole number")
+}
+
+func TestProfilesManager_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "profiles.json")
+	legacy := []byte(`[{"name": "api", "base_url": "https://api.example.com", "interval": 5}]`)
+	require.NoError(t, os.WriteFile(path, legacy, 0644))
+
+	pm := NewProfilesManagerForFile(path)
+	results, err := pm.Migrate(true)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 0, results[0].From)
+	assert.Equal(t, ConfigVersion, results[0].To)
+	assert.Equal(t, migrationSteps(0), results[0].Steps)
+	assert.Equal(t, legacy, results[0].Before)
+	assert.Contains(t, string(results[0].After), `"version": 1`)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, data)
+	assert.NoFileExists(t, backupPath(path, 1))
+
+	results, err = pm.Migrate(false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, results[0].After, data)
+	backup, err := os.ReadFile(backupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, legacy, backup)
+
+	results, err = pm.Migrate(false)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, pm.LoadProfiles())
+	assert.Len(t, pm.GetProfiles(), 1)
+}
+
+func TestProfilesManager_RejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: 99\nprofiles: []\n"), 0644))
+
+	pm := NewProfilesManagerForFile(path)
+	err := pm.LoadProfiles()
+	assert.ErrorContains(t, err, "version 99 is newer")
+}
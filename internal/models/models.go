@@ -1,23 +1,45 @@
 package models
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Profile struct {
-	Name     string            `json:"name"`
-	BaseURL  string            `json:"base_url"`
-	Route    string            `json:"route"`
-	Params   map[string]string `json:"params"`
-	Headers  map[string]string `json:"headers"`
-	Interval int               `json:"interval"`
+	Name        string              `json:"name"`
+	BaseURL     string              `json:"base_url"`
+	Route       string              `json:"route"`
+	Params      map[string]string   `json:"params"`
+	Headers     map[string]string   `json:"headers"`
+	Interval    int                 `json:"interval"`
+	Group       string              `json:"group,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Auth        *AuthConfig         `json:"auth,omitempty"`
+	Type        string              `json:"type,omitempty"`
+	Steps       []ScenarioStep      `json:"steps,omitempty"`
+	Schema      string              `json:"schema,omitempty"`
+	Snapshot    *SnapshotConfig     `json:"snapshot,omitempty"`
+	TCP         *TCPConfig          `json:"tcp,omitempty"`
+	GRPC        *GRPCConfig         `json:"grpc,omitempty"`
+	DNS         *DNSConfig          `json:"dns,omitempty"`
+	WebSocket   *WebSocketConfig    `json:"websocket,omitempty"`
+	GraphQL     *GraphQLConfig      `json:"graphql,omitempty"`
+	SSE         *SSEConfig          `json:"sse,omitempty"`
+	Hooks       []HookConfig        `json:"hooks,omitempty"`
+	Escalation  *EscalationPolicy   `json:"escalation,omitempty"`
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
+	Schedule    *ScheduleConfig     `json:"schedule,omitempty"`
+
+	BaseDir string `json:"-"`
 }
 
 func (p *Profile) GetFullURL() string {
@@ -42,85 +64,332 @@ func (p *Profile) GetFullURL() string {
 	return u.String()
 }
 
+func (p *Profile) Kind() string {
+	if p.Type == "" {
+		return ProfileTypeHTTP
+	}
+	return p.Type
+}
+
+func (p *Profile) UsesURL() bool {
+	switch p.Kind() {
+	case ProfileTypeHTTP, ProfileTypeScenario, ProfileTypeWebSocket, ProfileTypeGraphQL, ProfileTypeSSE:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Profile) Target() string {
+	if p.UsesURL() {
+		return p.GetFullURL()
+	}
+	return p.BaseURL
+}
+
 type PingResult struct {
 	Timestamp  time.Time
 	StatusCode int
 	Success    bool
 	Error      error
 	Duration   time.Duration
+	AuthError  error
+	Steps      []StepResult
+
+	SchemaViolations []SchemaViolation
+
+	Changed bool
+	Diff    []DiffLine
+
+	Detail  string
+	Timings []Timing
+
+	GraphQLErrors []string
+
+	Maintenance string
+}
+
+type Timing struct {
+	Name     string
+	Duration time.Duration
 }
 
 type ProfilesManager struct {
+	mu       sync.Mutex
 	profiles []Profile
 	filePath string
+
+	root      *profileFile
+	files     []*profileFile
+	sources   map[string]*profileFile
+	conflicts []ProfileConflict
+	loaded    bool
 }
 
-func NewProfilesManager() *ProfilesManager {
-	homeDir, _ := os.UserHomeDir()
-	configDir := filepath.Join(homeDir, ".route-keeper")
-	os.MkdirAll(configDir, 0755)
+func NewProfilesManager() (*ProfilesManager, error) {
+	dirs, err := DefaultDirs()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dirs.Config, 0755); err != nil {
+		return nil, fmt.Errorf("create config directory: %w", err)
+	}
+
+	return NewProfilesManagerForFile(dirs.ProfilesPath()), nil
+}
 
+func NewProfilesManagerForFile(filePath string) *ProfilesManager {
 	return &ProfilesManager{
 		profiles: []Profile{},
-		filePath: filepath.Join(configDir, "profiles.json"),
+		filePath: filePath,
+		sources:  make(map[string]*profileFile),
 	}
 }
 
+func (pm *ProfilesManager) ConfigDir() string {
+	return filepath.Dir(pm.filePath)
+}
+
+func (pm *ProfilesManager) lockPath() string {
+	return pm.filePath + ".lock"
+}
+
 func (pm *ProfilesManager) LoadProfiles() error {
-	if _, err := os.Stat(pm.filePath); os.IsNotExist(err) {
-		return nil
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	lock, err := acquireLock(pm.lockPath(), false)
+	if err != nil {
+		return err
 	}
+	defer lock.release()
 
-	data, err := os.ReadFile(pm.filePath)
+	files, err := loadProfileFiles(pm.filePath)
 	if err != nil {
 		return err
 	}
+	pm.adopt(files)
+	return validateFiles(files)
+}
+
+func (pm *ProfilesManager) ReloadIfChanged() (bool, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	lock, err := acquireLock(pm.lockPath(), false)
+	if err != nil {
+		return false, err
+	}
+	defer lock.release()
 
-	return json.Unmarshal(data, &pm.profiles)
+	files, err := loadProfileFiles(pm.filePath)
+	if err != nil {
+		return false, err
+	}
+	if pm.loaded && sameFiles(pm.files, files) {
+		return false, nil
+	}
+	pm.adopt(files)
+	return true, validateFiles(files)
+}
+
+func (pm *ProfilesManager) adopt(files []*profileFile) {
+	pm.files = files
+	pm.root = files[len(files)-1]
+	pm.loaded = true
+	pm.merge()
 }
 
 func (pm *ProfilesManager) SaveProfiles() error {
-	data, err := json.MarshalIndent(pm.profiles, "", "  ")
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.update(func() error { return nil })
+}
+
+func (pm *ProfilesManager) update(mutate func() error) error {
+	lock, err := acquireLock(pm.lockPath(), true)
 	if err != nil {
 		return err
 	}
+	defer lock.release()
+
+	if pm.loaded {
+		files, err := loadProfileFiles(pm.filePath)
+		if err != nil {
+			return fmt.Errorf("%s changed on disk and could not be reloaded: %w", pm.filePath, err)
+		}
+		if !sameFiles(pm.files, files) {
+			pm.adopt(files)
+		}
+	}
 
-	return os.WriteFile(pm.filePath, data, 0644)
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	pm.rootFile()
+	for _, file := range pm.files {
+		if !file.dirty {
+			continue
+		}
+		if err := file.save(); err != nil {
+			return err
+		}
+		file.dirty = false
+	}
+	return nil
 }
 
 func (pm *ProfilesManager) AddProfile(profile Profile) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.update(func() error {
+		pm.upsert(profile)
+		return nil
+	})
+}
+
+func (pm *ProfilesManager) upsert(profile Profile) {
 	for i, p := range pm.profiles {
 		if p.Name == profile.Name {
+			file := pm.sources[p.Name]
+			profile.BaseDir = file.dir()
 			pm.profiles[i] = profile
-			return pm.SaveProfiles()
+			file.replace(p.Name, profile)
+			return
 		}
 	}
 
+	root := pm.rootFile()
+	profile.BaseDir = root.dir()
+	root.profiles = append(root.profiles, profile)
+	root.dirty = true
+	pm.sources[profile.Name] = root
 	pm.profiles = append(pm.profiles, profile)
-	return pm.SaveProfiles()
+}
+
+func (pm *ProfilesManager) UpdateProfile(name string, profile Profile) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.update(func() error {
+		if profile.Name == name {
+			pm.upsert(profile)
+			return nil
+		}
+
+		file, ok := pm.sources[name]
+		if !ok {
+			return fmt.Errorf("profile not found")
+		}
+		if _, exists := pm.sources[profile.Name]; exists {
+			return fmt.Errorf("profile %q already exists", profile.Name)
+		}
+
+		profile.BaseDir = file.dir()
+		file.replace(name, profile)
+		for _, f := range pm.files {
+			if f != file {
+				f.remove(name)
+			}
+		}
+		delete(pm.sources, name)
+		pm.sources[profile.Name] = file
+
+		for i, p := range pm.profiles {
+			if p.Name == name {
+				pm.profiles[i] = profile
+			}
+		}
+		return nil
+	})
 }
 
 func (pm *ProfilesManager) GetProfiles() []Profile {
-	return pm.profiles
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return slices.Clone(pm.profiles)
 }
 
 func (pm *ProfilesManager) DeleteProfile(name string) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.update(func() error {
+		if !pm.forget(name) {
+			return fmt.Errorf("profile not found")
+		}
+		return nil
+	})
+}
+
+func (pm *ProfilesManager) forget(name string) bool {
 	for i, p := range pm.profiles {
 		if p.Name == name {
 			pm.profiles = append(pm.profiles[:i], pm.profiles[i+1:]...)
-			return pm.SaveProfiles()
+			for _, file := range pm.files {
+				file.remove(name)
+			}
+			delete(pm.sources, name)
+			return true
 		}
 	}
-	return fmt.Errorf("profile not found")
+	return false
 }
 
-type PingService struct{}
+const maxBodySize = 1 << 20
+
+type PingService struct {
+	tokens    *TokenCache
+	schemas   *SchemaCache
+	snapshots *SnapshotStore
+	checkers  map[string]Checker
+
+	maintenance []MaintenanceWindow
+}
 
 func NewPingService() *PingService {
-	return &PingService{}
+	ps := &PingService{
+		tokens:    NewTokenCache(),
+		schemas:   NewSchemaCache(),
+		snapshots: NewSnapshotStore(),
+		checkers:  make(map[string]Checker),
+	}
+
+	ps.Register(ProfileTypeHTTP, CheckerFunc(ps.pingHTTP))
+	ps.Register(ProfileTypeScenario, CheckerFunc(ps.runScenario))
+	ps.Register(ProfileTypeTCP, &TCPChecker{})
+	ps.Register(ProfileTypeGRPC, &GRPCChecker{})
+	ps.Register(ProfileTypeDNS, &DNSChecker{})
+	ps.Register(ProfileTypeWebSocket, &WebSocketChecker{})
+	ps.Register(ProfileTypeGraphQL, CheckerFunc(ps.pingGraphQL))
+	ps.Register(ProfileTypeSSE, CheckerFunc(ps.pingSSE))
+
+	return ps
 }
 
-func (ps *PingService) Ping(profile Profile) PingResult {
+func (ps *PingService) SetMaintenanceWindows(windows []MaintenanceWindow) {
+	ps.maintenance = windows
+}
+
+func (ps *PingService) InMaintenance(profile Profile, now time.Time) (MaintenanceWindow, bool) {
+	return ActiveMaintenance(profile, ps.maintenance, now)
+}
+
+func (ps *PingService) Snapshots() *SnapshotStore {
+	return ps.snapshots
+}
+
+type responseInspector func(body []byte, result *PingResult)
+
+func (ps *PingService) pingHTTP(profile Profile) PingResult {
+	return ps.doRequest(profile, "GET", nil, nil)
+}
+
+func (ps *PingService) doRequest(profile Profile, method string, body []byte, inspect responseInspector) PingResult {
 	start := time.Now()
 	result := PingResult{
 		Timestamp: start,
@@ -131,17 +400,31 @@ func (ps *PingService) Ping(profile Profile) PingResult {
 		Timeout: 30 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", profile.GetFullURL(), nil)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, profile.GetFullURL(), reader)
 	if err != nil {
 		result.Error = err
 		result.Duration = time.Since(start)
 		return result
 	}
 
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	for k, v := range profile.Headers {
 		req.Header.Set(k, v)
 	}
 
+	if err := ps.authorize(req, profile); err != nil {
+		result.AuthError = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		result.Error = err
@@ -150,9 +433,80 @@ func (ps *PingService) Ping(profile Profile) PingResult {
 	}
 	defer resp.Body.Close()
 
+	ps.checkUnauthorized(resp, profile)
+
 	result.StatusCode = resp.StatusCode
 	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	if inspect != nil || (result.Success && (profile.Schema != "" || profile.Snapshot != nil)) {
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			result.Error = err
+			result.Success = false
+			result.Duration = time.Since(start)
+			return result
+		}
+
+		if inspect != nil {
+			inspect(respBody, &result)
+		}
+
+		if profile.Schema != "" && result.Success {
+			if err := ps.validateSchema(respBody, profile, &result); err != nil {
+				result.Error = err
+				result.Success = false
+			}
+		}
+
+		if profile.Snapshot != nil && result.Success {
+			if err := ps.snapshots.Compare(profile, respBody, &result); err != nil {
+				result.Error = fmt.Errorf("snapshot: %w", err)
+			}
+		}
+	}
+
 	result.Duration = time.Since(start)
 
 	return result
 }
+
+func (ps *PingService) authorize(req *http.Request, profile Profile) error {
+	if profile.Auth == nil {
+		return nil
+	}
+
+	token, err := ps.tokens.Token(profile.Name, profile.Auth)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", token.AuthorizationHeader())
+	return nil
+}
+
+func (ps *PingService) checkUnauthorized(resp *http.Response, profile Profile) {
+	if resp.StatusCode == http.StatusUnauthorized && profile.Auth != nil {
+		ps.tokens.Invalidate(profile.Name, profile.Auth)
+	}
+}
+
+func (ps *PingService) validateSchema(body []byte, profile Profile, result *PingResult) error {
+	path, err := profile.resolvePath(profile.Schema)
+	if err != nil {
+		return fmt.Errorf("load schema: %w", err)
+	}
+	schema, err := ps.schemas.Load(path)
+	if err != nil {
+		return fmt.Errorf("load schema: %w", err)
+	}
+
+	violations, err := ValidateSchema(schema, body)
+	if err != nil {
+		return err
+	}
+
+	result.SchemaViolations = violations
+	if len(violations) > 0 {
+		result.Success = false
+	}
+	return nil
+}
@@ -61,7 +61,9 @@ func TestProfilesManager(t *testing.T) {
 	require.NoError(t, err)
 	defer os.RemoveAll(tempDir)
 
-	pm := NewProfilesManager()
+	t.Setenv(HomeEnv, t.TempDir())
+	pm, err := NewProfilesManager()
+	require.NoError(t, err)
 	assert.NotNil(t, pm)
 
 	profile := Profile{
@@ -84,8 +86,7 @@ func TestProfilesManager(t *testing.T) {
 	err = pm.SaveProfiles()
 	require.NoError(t, err)
 
-	pm2 := NewProfilesManager()
-	pm2.filePath = tempFile
+	pm2 := NewProfilesManagerForFile(tempFile)
 
 	err = pm2.LoadProfiles()
 	require.NoError(t, err)
@@ -116,8 +117,7 @@ func TestPingService(t *testing.T) {
 }
 
 func TestProfilesManager_EdgeCases(t *testing.T) {
-	pm := NewProfilesManager()
-	pm.filePath = "/invalid/path/profiles.json"
+	pm := NewProfilesManagerForFile("/invalid/path/profiles.json")
 
 	err := pm.SaveProfiles()
 	require.Error(t, err)
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AuthClientCredentials = "oauth2_client_credentials"
+	AuthRefreshToken      = "oauth2_refresh_token"
+)
+
+const tokenExpirySkew = 10 * time.Second
+
+type AuthConfig struct {
+	Type              string            `json:"type"`
+	TokenURL          string            `json:"token_url"`
+	ClientID          string            `json:"client_id,omitempty"`
+	ClientSecret      string            `json:"client_secret,omitempty"`
+	RefreshToken      string            `json:"refresh_token,omitempty"`
+	Scopes            []string          `json:"scopes,omitempty"`
+	ExtraParams       map[string]string `json:"extra_params,omitempty"`
+	CredentialsInBody bool              `json:"credentials_in_body,omitempty"`
+}
+
+type Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+func (t *Token) Valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || now.Add(tokenExpirySkew).Before(t.Expiry)
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type TokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*Token
+	locks  map[string]*sync.Mutex
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		tokens: make(map[string]*Token),
+		locks:  make(map[string]*sync.Mutex),
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}
+}
+
+func tokenCacheKey(profileName string, auth *AuthConfig) string {
+	return strings.Join([]string{profileName, auth.Type, auth.TokenURL, auth.ClientID}, "|")
+}
+
+func (tc *TokenCache) Token(profileName string, auth *AuthConfig) (*Token, error) {
+	key := tokenCacheKey(profileName, auth)
+
+	tc.mu.Lock()
+	lock, ok := tc.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		tc.locks[key] = lock
+	}
+	tc.mu.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	tc.mu.Lock()
+	cached := tc.tokens[key]
+	tc.mu.Unlock()
+	if cached.Valid(tc.now()) {
+		return cached, nil
+	}
+
+	refreshToken := auth.RefreshToken
+	if cached != nil && cached.RefreshToken != "" {
+		refreshToken = cached.RefreshToken
+	}
+
+	token, err := tc.fetch(auth, refreshToken)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if err != nil {
+		delete(tc.tokens, key)
+		return nil, err
+	}
+
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	tc.tokens[key] = token
+	return token, nil
+}
+
+func (tc *TokenCache) Invalidate(profileName string, auth *AuthConfig) {
+	key := tokenCacheKey(profileName, auth)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if cached, ok := tc.tokens[key]; ok {
+		tc.tokens[key] = &Token{RefreshToken: cached.RefreshToken}
+	}
+}
+
+func (tc *TokenCache) fetch(auth *AuthConfig, refreshToken string) (*Token, error) {
+	form := url.Values{}
+	switch auth.Type {
+	case AuthClientCredentials:
+		form.Set("grant_type", "client_credentials")
+	case AuthRefreshToken:
+		if refreshToken == "" {
+			return nil, fmt.Errorf("refresh token is required for %s", auth.Type)
+		}
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", refreshToken)
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+
+	if len(auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(auth.Scopes, " "))
+	}
+	for k, v := range auth.ExtraParams {
+		form.Set(k, v)
+	}
+	if auth.CredentialsInBody {
+		form.Set("client_id", auth.ClientID)
+		if auth.ClientSecret != "" {
+			form.Set("client_secret", auth.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequest("POST", auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !auth.CredentialsInBody && auth.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(auth.ClientID), url.QueryEscape(auth.ClientSecret))
+	}
+
+	resp, err := tc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var tr tokenResponse
+	jsonErr := json.Unmarshal(body, &tr)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if jsonErr == nil && tr.Error != "" {
+			if tr.ErrorDescription != "" {
+				return nil, fmt.Errorf("token endpoint returned %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+			}
+			return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, tr.Error)
+		}
+		return nil, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("invalid token response: %w", jsonErr)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	token := &Token{
+		AccessToken:  tr.AccessToken,
+		TokenType:    tr.TokenType,
+		RefreshToken: tr.RefreshToken,
+	}
+	if tr.ExpiresIn > 0 {
+		token.Expiry = tc.now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (t *Token) AuthorizationHeader() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
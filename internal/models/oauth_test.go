@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenServer(t *testing.T, expiresIn int, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		require.NoError(t, r.ParseForm())
+
+		user, pass, ok := r.BasicAuth()
+		if r.Form.Get("grant_type") == "client_credentials" && (!ok || user != "client" || pass != "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "token-" + r.Form.Get("grant_type"),
+			"token_type":    "bearer",
+			"refresh_token": "rotated",
+			"expires_in":    expiresIn,
+		})
+	}))
+}
+
+func TestTokenCache_ClientCredentials(t *testing.T) {
+	var calls int32
+	server := newTokenServer(t, 3600, &calls)
+	defer server.Close()
+
+	auth := &AuthConfig{
+		Type:         AuthClientCredentials,
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}
+
+	tc := NewTokenCache()
+	token, err := tc.Token("api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-client_credentials", token.AuthorizationHeader())
+
+	_, err = tc.Token("api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	tc.now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err = tc.Token("api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	tc.Invalidate("api", auth)
+	_, err = tc.Token("api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestTokenCache_RefreshToken(t *testing.T) {
+	var calls int32
+	server := newTokenServer(t, 0, &calls)
+	defer server.Close()
+
+	tc := NewTokenCache()
+	_, err := tc.Token("api", &AuthConfig{Type: AuthRefreshToken, TokenURL: server.URL})
+	assert.Error(t, err)
+
+	auth := &AuthConfig{Type: AuthRefreshToken, TokenURL: server.URL, RefreshToken: "initial"}
+	token, err := tc.Token("api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, "token-refresh_token", token.AccessToken)
+	assert.Equal(t, "rotated", token.RefreshToken)
+	assert.True(t, token.Expiry.IsZero())
+}
+
+func TestTokenCache_SlowFetchDoesNotBlockOtherProfiles(t *testing.T) {
+	var slowCalls, fastCalls int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowCalls, 1)
+		started <- struct{}{}
+		<-release
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "slow", "expires_in": 3600})
+	}))
+	defer slow.Close()
+	fast := newTokenServer(t, 3600, &fastCalls)
+	defer fast.Close()
+
+	tc := NewTokenCache()
+	slowAuth := &AuthConfig{Type: AuthRefreshToken, TokenURL: slow.URL, RefreshToken: "initial"}
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tc.Token("slow", slowAuth)
+			assert.NoError(t, err)
+			assert.Equal(t, "slow", token.AccessToken)
+		}()
+	}
+	<-started
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := tc.Token("fast", &AuthConfig{Type: AuthRefreshToken, TokenURL: fast.URL, RefreshToken: "initial"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("token fetch for another profile waited on the slow endpoint")
+	}
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slowCalls))
+}
+
+func TestPingService_OAuth(t *testing.T) {
+	var calls int32
+	tokenServer := newTokenServer(t, 3600, &calls)
+	defer tokenServer.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer api.Close()
+
+	ps := NewPingService()
+	profile := Profile{
+		Name:    "oauth",
+		BaseURL: api.URL,
+		Auth: &AuthConfig{
+			Type:         AuthClientCredentials,
+			TokenURL:     tokenServer.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+		},
+	}
+
+	result := ps.Ping(profile)
+	assert.NoError(t, result.AuthError)
+	assert.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	profile.Name = "bad-credentials"
+	profile.Auth.ClientSecret = "wrong"
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Zero(t, result.StatusCode)
+	assert.NoError(t, result.Error)
+	require.Error(t, result.AuthError)
+	assert.Contains(t, result.AuthError.Error(), "invalid_client")
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	HomeEnv = "ROUTE_KEEPER_HOME"
+	appName = "route-keeper"
+)
+
+type Dirs struct {
+	Config string
+	Data   string
+	State  string
+}
+
+func DefaultDirs() (Dirs, error) {
+	if home := os.Getenv(HomeEnv); home != "" {
+		abs, err := filepath.Abs(home)
+		if err != nil {
+			return Dirs{}, fmt.Errorf("%s: %w", HomeEnv, err)
+		}
+		return Dirs{Config: abs, Data: abs, State: abs}, nil
+	}
+
+	config, err := baseDir("XDG_CONFIG_HOME", os.UserConfigDir)
+	if err != nil {
+		return Dirs{}, fmt.Errorf("locate config directory: %w", err)
+	}
+	data, err := baseDir("XDG_DATA_HOME", userDataDir)
+	if err != nil {
+		return Dirs{}, fmt.Errorf("locate data directory: %w", err)
+	}
+	state, err := baseDir("XDG_STATE_HOME", userStateDir)
+	if err != nil {
+		return Dirs{}, fmt.Errorf("locate state directory: %w", err)
+	}
+	return Dirs{Config: config, Data: data, State: state}, nil
+}
+
+func baseDir(env string, fallback func() (string, error)) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName), nil
+	}
+	dir, err := fallback()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+func userDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%LocalAppData% is not defined")
+	case "darwin", "ios":
+		return os.UserConfigDir()
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+func userStateDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios":
+		return userDataDir()
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state"), nil
+}
+
+func (d Dirs) Ensure() error {
+	for _, dir := range []string{d.Config, d.Data, d.State} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func (d Dirs) ProfilesPath() string {
+	return filepath.Join(d.Config, "profiles.json")
+}
+
+func (d Dirs) SettingsPath() string {
+	return filepath.Join(d.Config, "settings.json")
+}
+
+func (d Dirs) HistoryPath() string {
+	return filepath.Join(d.Data, "history.jsonl")
+}
+
+func (d Dirs) AlertsPath() string {
+	return filepath.Join(d.State, "alerts.json")
+}
+
+func (d Dirs) TUIAlertsPath() string {
+	return filepath.Join(d.State, "alerts-tui.json")
+}
+
+func (d Dirs) NotificationLogPath() string {
+	return filepath.Join(d.State, "notifications.log")
+}
+
+func (d Dirs) dirFor(name string) string {
+	switch {
+	case strings.HasPrefix(name, "history."):
+		return d.Data
+	case strings.HasPrefix(name, "alerts"), strings.HasPrefix(name, "notifications.log"):
+		return d.State
+	}
+	return d.Config
+}
+
+func LegacyDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "."+appName), nil
+}
+
+type LegacyMove struct {
+	From string
+	To   string
+}
+
+func MigrateLegacyDir(dirs Dirs) ([]LegacyMove, error) {
+	if os.Getenv(HomeEnv) != "" {
+		return nil, nil
+	}
+	legacy, err := LegacyDir()
+	if err != nil {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(legacy)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []LegacyMove
+	var errs []error
+	for _, entry := range entries {
+		from := filepath.Join(legacy, entry.Name())
+		dir := dirs.dirFor(entry.Name())
+		if sameDir(legacy, dir) {
+			continue
+		}
+		to := filepath.Join(dir, entry.Name())
+
+		if _, err := os.Lstat(to); err == nil {
+			errs = append(errs, fmt.Errorf("%s: left in place because %s already exists", from, to))
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := movePath(from, to); err != nil {
+			errs = append(errs, fmt.Errorf("move %s: %w", from, err))
+			continue
+		}
+		moves = append(moves, LegacyMove{From: from, To: to})
+	}
+
+	if len(errs) == 0 {
+		os.Remove(legacy)
+	}
+	return moves, errors.Join(errs...)
+}
+
+func sameDir(a, b string) bool {
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+func movePath(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	if err := copyPath(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func copyPath(from, to string) error {
+	info, err := os.Lstat(from)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		if err := os.MkdirAll(to, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(from)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := copyPath(filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(from)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, to)
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG fallbacks are only used on Linux")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(HomeEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	dirs, err := DefaultDirs()
+	require.NoError(t, err)
+	assert.Equal(t, Dirs{
+		Config: filepath.Join(home, ".config", "route-keeper"),
+		Data:   filepath.Join(home, ".local", "share", "route-keeper"),
+		State:  filepath.Join(home, ".local", "state", "route-keeper"),
+	}, dirs)
+
+	t.Setenv("XDG_CONFIG_HOME", "/etc/xdg")
+	t.Setenv("XDG_DATA_HOME", "relative/data")
+	t.Setenv("XDG_STATE_HOME", "/var/state")
+	dirs, err = DefaultDirs()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/xdg/route-keeper", dirs.Config)
+	assert.Equal(t, filepath.Join(home, ".local", "share", "route-keeper"), dirs.Data)
+	assert.Equal(t, "/var/state/route-keeper", dirs.State)
+
+	override := t.TempDir()
+	t.Setenv(HomeEnv, override)
+	dirs, err = DefaultDirs()
+	require.NoError(t, err)
+	assert.Equal(t, Dirs{Config: override, Data: override, State: override}, dirs)
+	assert.Equal(t, filepath.Join(override, "profiles.json"), dirs.ProfilesPath())
+
+	t.Setenv(HomeEnv, "")
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	_, err = DefaultDirs()
+	assert.ErrorContains(t, err, "locate config directory")
+}
+
+func TestMigrateLegacyDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(HomeEnv, "")
+
+	legacy := filepath.Join(home, ".route-keeper")
+	for _, name := range []string{"profiles.json", "settings.json", "history.jsonl", "alerts.json", "notifications.log", "conf.d/db.yaml"} {
+		writeFile(t, filepath.Join(legacy, name), name)
+	}
+
+	root := t.TempDir()
+	dirs := Dirs{
+		Config: filepath.Join(root, "config"),
+		Data:   filepath.Join(root, "data"),
+		State:  filepath.Join(root, "state"),
+	}
+	writeFile(t, dirs.SettingsPath(), "new settings")
+
+	moves, err := MigrateLegacyDir(dirs)
+	assert.ErrorContains(t, err, "settings.json: left in place because")
+	assert.Len(t, moves, 5)
+
+	for path, want := range map[string]string{
+		dirs.ProfilesPath():                          "profiles.json",
+		dirs.SettingsPath():                          "new settings",
+		dirs.HistoryPath():                           "history.jsonl",
+		dirs.AlertsPath():                            "alerts.json",
+		dirs.NotificationLogPath():                   "notifications.log",
+		filepath.Join(dirs.Config, "conf.d/db.yaml"): "conf.d/db.yaml",
+	} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+	assert.FileExists(t, filepath.Join(legacy, "settings.json"))
+
+	require.NoError(t, os.Remove(filepath.Join(legacy, "settings.json")))
+	moves, err = MigrateLegacyDir(dirs)
+	require.NoError(t, err)
+	assert.Empty(t, moves)
+	assert.NoDirExists(t, legacy)
+}
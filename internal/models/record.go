@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type StepRecord struct {
+	Name       string `json:"name"`
+	StatusCode int    `json:"status_code"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type TimingRecord struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type PingRecord struct {
+	Profile          string            `json:"profile"`
+	Timestamp        time.Time         `json:"timestamp"`
+	StatusCode       int               `json:"status_code"`
+	Success          bool              `json:"success"`
+	Error            string            `json:"error,omitempty"`
+	AuthError        string            `json:"auth_error,omitempty"`
+	DurationMs       int64             `json:"duration_ms"`
+	Steps            []StepRecord      `json:"steps,omitempty"`
+	SchemaViolations []SchemaViolation `json:"schema_violations,omitempty"`
+	Changed          bool              `json:"changed,omitempty"`
+	Diff             []DiffLine        `json:"diff,omitempty"`
+	Detail           string            `json:"detail,omitempty"`
+	Timings          []TimingRecord    `json:"timings,omitempty"`
+	GraphQLErrors    []string          `json:"graphql_errors,omitempty"`
+	Maintenance      string            `json:"maintenance,omitempty"`
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func stringError(s string) error {
+	if s == "" {
+		return nil
+	}
+	return errors.New(s)
+}
+
+func NewPingRecord(profile string, result PingResult) PingRecord {
+	record := PingRecord{
+		Profile:          profile,
+		Timestamp:        result.Timestamp,
+		StatusCode:       result.StatusCode,
+		Success:          result.Success,
+		Error:            errorString(result.Error),
+		AuthError:        errorString(result.AuthError),
+		DurationMs:       result.Duration.Milliseconds(),
+		SchemaViolations: result.SchemaViolations,
+		Changed:          result.Changed,
+		Diff:             result.Diff,
+		Detail:           result.Detail,
+		GraphQLErrors:    result.GraphQLErrors,
+		Maintenance:      result.Maintenance,
+	}
+
+	for _, step := range result.Steps {
+		record.Steps = append(record.Steps, StepRecord{
+			Name:       step.Name,
+			StatusCode: step.StatusCode,
+			Success:    step.Success,
+			Error:      errorString(step.Error),
+			DurationMs: step.Duration.Milliseconds(),
+		})
+	}
+
+	for _, timing := range result.Timings {
+		record.Timings = append(record.Timings, TimingRecord{
+			Name:       timing.Name,
+			DurationMs: timing.Duration.Milliseconds(),
+		})
+	}
+
+	return record
+}
+
+func (r PingRecord) Result() PingResult {
+	result := PingResult{
+		Timestamp:        r.Timestamp,
+		StatusCode:       r.StatusCode,
+		Success:          r.Success,
+		Error:            stringError(r.Error),
+		AuthError:        stringError(r.AuthError),
+		Duration:         time.Duration(r.DurationMs) * time.Millisecond,
+		SchemaViolations: r.SchemaViolations,
+		Changed:          r.Changed,
+		Diff:             r.Diff,
+		Detail:           r.Detail,
+		GraphQLErrors:    r.GraphQLErrors,
+		Maintenance:      r.Maintenance,
+	}
+
+	for _, step := range r.Steps {
+		result.Steps = append(result.Steps, StepResult{
+			Name:       step.Name,
+			StatusCode: step.StatusCode,
+			Success:    step.Success,
+			Error:      stringError(step.Error),
+			Duration:   time.Duration(step.DurationMs) * time.Millisecond,
+		})
+	}
+
+	for _, timing := range r.Timings {
+		result.Timings = append(result.Timings, Timing{
+			Name:     timing.Name,
+			Duration: time.Duration(timing.DurationMs) * time.Millisecond,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingRecord_RoundTrip(t *testing.T) {
+	result := PingResult{
+		Timestamp:  time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC),
+		StatusCode: 502,
+		Error:      errors.New("bad gateway"),
+		Duration:   1500 * time.Millisecond,
+		Steps: []StepResult{
+			{Name: "login", StatusCode: 200, Success: true, Duration: 20 * time.Millisecond},
+			{Name: "me", StatusCode: 502, Error: errors.New("unexpected status 502")},
+		},
+		SchemaViolations: []SchemaViolation{{Path: "/id", Message: "missing"}},
+		Changed:          true,
+		Diff:             []DiffLine{{Op: DiffInsert, Text: "x"}},
+		Timings:          []Timing{{Name: "handshake", Duration: 12 * time.Millisecond}},
+	}
+
+	data, err := json.Marshal(NewPingRecord("api", result))
+	require.NoError(t, err)
+
+	var record PingRecord
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, "api", record.Profile)
+
+	decoded := record.Result()
+	assert.Equal(t, result.Timestamp, decoded.Timestamp)
+	assert.Equal(t, result.StatusCode, decoded.StatusCode)
+	assert.False(t, decoded.Success)
+	assert.EqualError(t, decoded.Error, "bad gateway")
+	assert.NoError(t, decoded.AuthError)
+	assert.Equal(t, result.Duration, decoded.Duration)
+	require.Len(t, decoded.Steps, 2)
+	assert.NoError(t, decoded.Steps[0].Error)
+	assert.EqualError(t, decoded.Steps[1].Error, "unexpected status 502")
+	assert.Equal(t, result.SchemaViolations, decoded.SchemaViolations)
+	assert.Equal(t, result.Diff, decoded.Diff)
+	assert.Equal(t, result.Timings, decoded.Timings)
+}
@@ -0,0 +1,258 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	ProfileTypeHTTP     = "http"
+	ProfileTypeScenario = "scenario"
+)
+
+const (
+	ExtractJSON   = "json"
+	ExtractHeader = "header"
+	ExtractRegex  = "regex"
+)
+
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+type Extraction struct {
+	Var  string `json:"var"`
+	From string `json:"from"`
+	Path string `json:"path"`
+}
+
+type ScenarioStep struct {
+	Name         string            `json:"name"`
+	Method       string            `json:"method,omitempty"`
+	Route        string            `json:"route"`
+	Params       map[string]string `json:"params,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body,omitempty"`
+	ExpectStatus int               `json:"expect_status,omitempty"`
+	Extract      []Extraction      `json:"extract,omitempty"`
+}
+
+type StepResult struct {
+	Name       string
+	StatusCode int
+	Success    bool
+	Error      error
+	Duration   time.Duration
+}
+
+func ExpandTemplate(s string, vars map[string]string) (string, error) {
+	var missing []string
+	expanded := templateVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVarPattern.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return expanded, fmt.Errorf("undefined variable %q", missing[0])
+	}
+	return expanded, nil
+}
+
+func (s *ScenarioStep) buildRequest(profile Profile, vars map[string]string) (*http.Request, error) {
+	route, err := ExpandTemplate(s.Route, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	target := Profile{BaseURL: profile.BaseURL, Route: route, Params: map[string]string{}}
+	if strings.HasPrefix(route, "http://") || strings.HasPrefix(route, "https://") {
+		target = Profile{BaseURL: route, Params: map[string]string{}}
+	}
+	for k, v := range s.Params {
+		if target.Params[k], err = ExpandTemplate(v, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := ExpandTemplate(s.Body, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(s.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, target.GetFullURL(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range profile.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range s.Headers {
+		value, err := ExpandTemplate(v, vars)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(k, value)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func (s *ScenarioStep) expectsStatus(code int) bool {
+	if s.ExpectStatus != 0 {
+		return code == s.ExpectStatus
+	}
+	return code >= 200 && code < 300
+}
+
+func (e Extraction) apply(resp *http.Response, body []byte) (string, error) {
+	switch e.From {
+	case ExtractJSON:
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return "", fmt.Errorf("response is not JSON: %w", err)
+		}
+		value, ok := LookupJSONPath(doc, e.Path)
+		if !ok {
+			return "", fmt.Errorf("json path %q not found", e.Path)
+		}
+		return jsonValueString(value), nil
+	case ExtractHeader:
+		value := resp.Header.Get(e.Path)
+		if value == "" {
+			return "", fmt.Errorf("header %q not found", e.Path)
+		}
+		return value, nil
+	case ExtractRegex:
+		re, err := regexp.Compile(e.Path)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("pattern %q did not match", e.Path)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	default:
+		return "", fmt.Errorf("unsupported extraction source %q", e.From)
+	}
+}
+
+func (ps *PingService) runStep(client *http.Client, profile Profile, step ScenarioStep, vars map[string]string) (StepResult, error) {
+	start := time.Now()
+	result := StepResult{Name: step.Name}
+
+	req, err := step.buildRequest(profile, vars)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	if err := ps.authorize(req, profile); err != nil {
+		result.Duration = time.Since(start)
+		return result, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	ps.checkUnauthorized(resp, profile)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	result.StatusCode = resp.StatusCode
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+
+	if !step.expectsStatus(resp.StatusCode) {
+		result.Error = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	for _, extraction := range step.Extract {
+		value, err := extraction.apply(resp, body)
+		if err != nil {
+			result.Error = fmt.Errorf("extract %s: %w", extraction.Var, err)
+			return result, nil
+		}
+		vars[extraction.Var] = value
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+func (ps *PingService) runScenario(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	if len(profile.Steps) == 0 {
+		result.Error = fmt.Errorf("scenario has no steps")
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	vars := make(map[string]string)
+	for i, step := range profile.Steps {
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+
+		stepResult, authErr := ps.runStep(client, profile, step, vars)
+		result.Steps = append(result.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+
+		if authErr != nil {
+			result.AuthError = authErr
+			result.Duration = time.Since(start)
+			return result
+		}
+		if stepResult.Error != nil {
+			result.Error = fmt.Errorf("%s: %w", step.Name, stepResult.Error)
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Duration = time.Since(start)
+	return result
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScenarioServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != `{"user":"houston"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("X-Session", "abc123")
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"token": "t0k3n", "user_id": 42},
+		})
+	})
+	mux.HandleFunc("/users/42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" || r.Header.Get("X-Session") != "abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`<p>Welcome back, Houston (v2.1)</p>`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{"token": "abc", "id": "7"}
+
+	out, err := ExpandTemplate("/users/{{id}}?t={{ token }}", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "/users/7?t=abc", out)
+
+	_, err = ExpandTemplate("/users/{{missing}}", vars)
+	assert.EqualError(t, err, `undefined variable "missing"`)
+}
+
+func TestPingService_Scenario(t *testing.T) {
+	server := newScenarioServer(t)
+	defer server.Close()
+
+	profile := Profile{
+		Name:    "login flow",
+		BaseURL: server.URL,
+		Type:    ProfileTypeScenario,
+		Steps: []ScenarioStep{
+			{
+				Name:   "login",
+				Method: "post",
+				Route:  "/login",
+				Body:   `{"user":"houston"}`,
+				Extract: []Extraction{
+					{Var: "token", From: ExtractJSON, Path: "$.data.token"},
+					{Var: "user_id", From: ExtractJSON, Path: "data.user_id"},
+					{Var: "session", From: ExtractHeader, Path: "X-Session"},
+				},
+			},
+			{
+				Name:  "me",
+				Route: "/users/{{user_id}}",
+				Headers: map[string]string{
+					"Authorization": "Bearer {{token}}",
+					"X-Session":     "{{session}}",
+				},
+				Extract: []Extraction{
+					{Var: "version", From: ExtractRegex, Path: `\(v([0-9.]+)\)`},
+				},
+			},
+		},
+	}
+
+	ps := NewPingService()
+	result := ps.Ping(profile)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	require.Len(t, result.Steps, 2)
+	assert.Equal(t, "login", result.Steps[0].Name)
+	assert.True(t, result.Steps[1].Success)
+
+	profile.Steps[1].Headers["Authorization"] = "Bearer wrong"
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	require.Len(t, result.Steps, 2)
+	assert.Equal(t, http.StatusUnauthorized, result.Steps[1].StatusCode)
+	assert.EqualError(t, result.Error, "me: unexpected status 401")
+
+	profile.Steps[0].Extract[0].Path = "data.missing"
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Len(t, result.Steps, 1)
+	assert.Contains(t, result.Error.Error(), `json path "data.missing" not found`)
+}
+
+func TestPingService_EmptyScenario(t *testing.T) {
+	result := NewPingService().Ping(Profile{Name: "empty", Type: ProfileTypeScenario})
+	assert.False(t, result.Success)
+	assert.Error(t, result.Error)
+}
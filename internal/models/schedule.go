@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultIntervalMinutes = 5
+	defaultInterval        = DefaultIntervalMinutes * time.Minute
+)
+
+type ScheduleConfig struct {
+	Cron          string       `json:"cron,omitempty"`
+	TimeZone      string       `json:"time_zone,omitempty"`
+	ActiveHours   []TimeWindow `json:"active_hours,omitempty"`
+	JitterSeconds int          `json:"jitter_seconds,omitempty"`
+}
+
+func (s *ScheduleConfig) location() (*time.Location, error) {
+	if s == nil || s.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(s.TimeZone)
+}
+
+func (s *ScheduleConfig) activeWindows() ([]resolvedWindow, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	windows := make([]resolvedWindow, 0, len(s.ActiveHours))
+	for _, w := range s.ActiveHours {
+		if w.TimeZone == "" {
+			w.TimeZone = s.TimeZone
+		}
+		r, err := w.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("active hours: %w", err)
+		}
+		windows = append(windows, r)
+	}
+	return windows, nil
+}
+
+func (s *ScheduleConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	if s.Cron != "" {
+		if _, err := ParseCron(s.Cron); err != nil {
+			return err
+		}
+	}
+	if s.JitterSeconds < 0 {
+		return errors.New("jitter_seconds must not be negative")
+	}
+	_, err := s.activeWindows()
+	return err
+}
+
+func (s *ScheduleConfig) Jitter() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(s.JitterSeconds) * time.Second
+}
+
+func (p *Profile) IntervalDuration() time.Duration {
+	if p.Interval <= 0 {
+		return defaultInterval
+	}
+	return time.Duration(p.Interval) * time.Minute
+}
+
+func (p *Profile) ActiveAt(t time.Time) bool {
+	windows, err := p.Schedule.activeWindows()
+	if err != nil || len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Profile) NextRun(after time.Time) (time.Time, error) {
+	if err := p.Schedule.Validate(); err != nil {
+		return time.Time{}, err
+	}
+
+	loc, _ := p.Schedule.location()
+	windows, _ := p.Schedule.activeWindows()
+
+	next := func(t time.Time) time.Time { return t.Add(p.IntervalDuration()) }
+	if p.Schedule != nil && p.Schedule.Cron != "" {
+		cron, _ := ParseCron(p.Schedule.Cron)
+		next = func(t time.Time) time.Time { return cron.Next(t.In(loc)) }
+	}
+
+	t := next(after)
+	for i := 0; i < 1000; i++ {
+		if t.IsZero() {
+			break
+		}
+		if p.ActiveAt(t) {
+			return t, nil
+		}
+
+		var start time.Time
+		for _, w := range windows {
+			if s, ok := w.nextStart(t); ok && (start.IsZero() || s.Before(start)) {
+				start = s
+			}
+		}
+		if start.IsZero() {
+			break
+		}
+		if p.Schedule.Cron != "" {
+			t = next(start.Add(-time.Nanosecond))
+		} else {
+			t = start
+		}
+	}
+	return time.Time{}, errors.New("schedule has no upcoming run")
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleConfig_Validate(t *testing.T) {
+	var nilSchedule *ScheduleConfig
+	assert.NoError(t, nilSchedule.Validate())
+
+	assert.ErrorContains(t, (&ScheduleConfig{Cron: "@sometimes"}).Validate(), "must have 5 fields")
+	assert.ErrorContains(t, (&ScheduleConfig{TimeZone: "Nowhere/City"}).Validate(), "unknown time zone")
+	assert.ErrorContains(t, (&ScheduleConfig{JitterSeconds: -1}).Validate(), "jitter_seconds")
+	assert.ErrorContains(t, (&ScheduleConfig{ActiveHours: []TimeWindow{{Start: "9am", End: "17:00"}}}).Validate(), "active hours: invalid start")
+	assert.NoError(t, (&ScheduleConfig{Cron: "*/5 * * * *", JitterSeconds: 30}).Validate())
+}
+
+func TestProfile_NextRun(t *testing.T) {
+	now := time.Date(2025, 6, 11, 16, 58, 0, 0, time.UTC)
+	businessHours := []TimeWindow{{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}}
+
+	tests := []struct {
+		name    string
+		profile Profile
+		want    time.Time
+	}{
+		{
+			name:    "interval",
+			profile: Profile{Interval: 10},
+			want:    now.Add(10 * time.Minute),
+		},
+		{
+			name:    "default interval",
+			profile: Profile{},
+			want:    now.Add(5 * time.Minute),
+		},
+		{
+			name:    "interval inside active hours",
+			profile: Profile{Interval: 1, Schedule: &ScheduleConfig{TimeZone: "UTC", ActiveHours: businessHours}},
+			want:    now.Add(time.Minute),
+		},
+		{
+			name:    "interval skips to next active window",
+			profile: Profile{Interval: 5, Schedule: &ScheduleConfig{TimeZone: "UTC", ActiveHours: businessHours}},
+			want:    time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "cron",
+			profile: Profile{Schedule: &ScheduleConfig{Cron: "0 * * * *", TimeZone: "UTC"}},
+			want:    time.Date(2025, 6, 11, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "cron restricted to active hours",
+			profile: Profile{Schedule: &ScheduleConfig{Cron: "30 * * * *", TimeZone: "UTC", ActiveHours: businessHours}},
+			want:    time.Date(2025, 6, 12, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "cron over the weekend",
+			profile: Profile{Schedule: &ScheduleConfig{Cron: "0 17 * * *", TimeZone: "America/New_York", ActiveHours: []TimeWindow{{Start: "16:00", End: "18:00", Days: []string{"sat"}}}}},
+			want:    time.Date(2025, 6, 14, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := tt.profile.NextRun(now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(next), "want %v, got %v", tt.want, next)
+		})
+	}
+
+	expired := Profile{Interval: 1, Schedule: &ScheduleConfig{ActiveHours: []TimeWindow{{Start: "2025-01-01 00:00", End: "2025-01-02 00:00"}}}}
+	_, err := expired.NextRun(now)
+	assert.ErrorContains(t, err, "no upcoming run")
+}
+
+func TestProfile_ActiveAt(t *testing.T) {
+	profile := Profile{Schedule: &ScheduleConfig{
+		TimeZone:    "Europe/Lisbon",
+		ActiveHours: []TimeWindow{{Start: "08:00", End: "20:00"}},
+	}}
+
+	assert.True(t, profile.ActiveAt(time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)))
+	assert.False(t, profile.ActiveAt(time.Date(2025, 1, 10, 20, 0, 0, 0, time.UTC)))
+	assert.True(t, (&Profile{}).ActiveAt(time.Now()))
+}
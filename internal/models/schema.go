@@ -0,0 +1,122 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var schemaMessagePrinter = message.NewPrinter(language.English)
+
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+type cachedSchema struct {
+	schema  *jsonschema.Schema
+	modTime time.Time
+}
+
+type SchemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]cachedSchema
+}
+
+func NewSchemaCache() *SchemaCache {
+	return &SchemaCache{
+		schemas: make(map[string]cachedSchema),
+	}
+}
+
+func expandPath(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(homeDir, path[2:])
+	}
+	return filepath.Abs(path)
+}
+
+func (p *Profile) resolvePath(path string) (string, error) {
+	if p.BaseDir != "" && !filepath.IsAbs(path) && !strings.HasPrefix(path, "~/") {
+		path = filepath.Join(p.BaseDir, path)
+	}
+	return expandPath(path)
+}
+
+func (sc *SchemaCache) Load(path string) (*jsonschema.Schema, error) {
+	absPath, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if cached, ok := sc.schemas[absPath]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.schema, nil
+	}
+
+	schema, err := jsonschema.NewCompiler().Compile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.schemas[absPath] = cachedSchema{schema: schema, modTime: info.ModTime()}
+	return schema, nil
+}
+
+func ValidateSchema(schema *jsonschema.Schema, body []byte) ([]SchemaViolation, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return []SchemaViolation{{Path: "/", Message: "response is not valid JSON"}}, nil
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	var violations []SchemaViolation
+	collectSchemaViolations(validationErr, &violations)
+	return violations, nil
+}
+
+func collectSchemaViolations(err *jsonschema.ValidationError, violations *[]SchemaViolation) {
+	if len(err.Causes) == 0 {
+		*violations = append(*violations, SchemaViolation{
+			Path:    "/" + strings.Join(err.InstanceLocation, "/"),
+			Message: err.ErrorKind.LocalizedString(schemaMessagePrinter),
+		})
+		return
+	}
+	for _, cause := range err.Causes {
+		collectSchemaViolations(cause, violations)
+	}
+}
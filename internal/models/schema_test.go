@@ -0,0 +1,81 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "name"],
+  "properties": {
+    "id": { "type": "integer" },
+    "name": { "type": "string" }
+  }
+}`
+
+func writeSchema(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "user.schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestValidateSchema(t *testing.T) {
+	path := writeSchema(t, t.TempDir(), userSchema)
+
+	schema, err := NewSchemaCache().Load(path)
+	require.NoError(t, err)
+
+	violations, err := ValidateSchema(schema, []byte(`{"id": 1, "name": "houston"}`))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = ValidateSchema(schema, []byte(`{"id": "1"}`))
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	paths := []string{violations[0].Path, violations[1].Path}
+	assert.Contains(t, paths, "/")
+	assert.Contains(t, paths, "/id")
+
+	violations, err = ValidateSchema(schema, []byte(`not json`))
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "response is not valid JSON", violations[0].Message)
+}
+
+func TestPingService_Schema(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSchema(t, dir, userSchema)
+
+	body := `{"id": 1, "name": "houston"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ps := NewPingService()
+	profile := Profile{Name: "users", BaseURL: server.URL, Schema: path}
+
+	result := ps.Ping(profile)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.SchemaViolations)
+
+	body = `{"id": 1}`
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	require.Len(t, result.SchemaViolations, 1)
+	assert.Contains(t, result.SchemaViolations[0].Message, "name")
+
+	profile.Schema = filepath.Join(dir, "missing.json")
+	result = ps.Ping(profile)
+	assert.False(t, result.Success)
+	assert.Error(t, result.Error)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+)
+
+type NotificationSettings struct {
+	Bell bool   `json:"bell,omitempty"`
+	OSC  string `json:"osc,omitempty"`
+	Exec string `json:"exec,omitempty"`
+}
+
+const (
+	HookOnDown = "down"
+	HookOnUp   = "up"
+)
+
+type HookConfig struct {
+	Command        string `json:"command"`
+	On             string `json:"on,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+func (h HookConfig) Matches(event string) bool {
+	return h.On == "" || h.On == event
+}
+
+const (
+	EmailTLSStartTLS = "starttls"
+	EmailTLSImplicit = "implicit"
+	EmailTLSNone     = "none"
+)
+
+type EmailSettings struct {
+	Host         string   `json:"host"`
+	Port         int      `json:"port,omitempty"`
+	TLS          string   `json:"tls,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	From         string   `json:"from"`
+	To           []string `json:"to"`
+	Subject      string   `json:"subject,omitempty"`
+	Body         string   `json:"body,omitempty"`
+	BatchSeconds int      `json:"batch_seconds,omitempty"`
+}
+
+type EscalationStep struct {
+	AfterFailures int      `json:"after_failures,omitempty"`
+	AfterMinutes  int      `json:"after_minutes,omitempty"`
+	Channels      []string `json:"channels,omitempty"`
+}
+
+type EscalationPolicy struct {
+	Steps         []EscalationStep `json:"steps"`
+	RepeatMinutes int              `json:"repeat_minutes,omitempty"`
+}
+
+type Settings struct {
+	Notifications NotificationSettings `json:"notifications"`
+	Hooks         []HookConfig         `json:"hooks,omitempty"`
+	Email         *EmailSettings       `json:"email,omitempty"`
+	Escalation    *EscalationPolicy    `json:"escalation,omitempty"`
+	Maintenance   []MaintenanceWindow  `json:"maintenance,omitempty"`
+}
+
+func LoadSettings(path string) (Settings, error) {
+	var settings Settings
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	err = json.Unmarshal(data, &settings)
+	return settings, err
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+
+	settings, err := LoadSettings(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, Settings{}, settings)
+
+	path := filepath.Join(dir, "settings.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"notifications":{"bell":true,"osc":"777","exec":"notify-send"}}`), 0644))
+	settings, err = LoadSettings(path)
+	require.NoError(t, err)
+	assert.Equal(t, NotificationSettings{Bell: true, OSC: "777", Exec: "notify-send"}, settings.Notifications)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0644))
+	_, err = LoadSettings(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const maxDiffCells = 4_000_000
+
+type SnapshotConfig struct {
+	Baseline    string   `json:"baseline,omitempty"`
+	IgnorePaths []string `json:"ignore_paths,omitempty"`
+}
+
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+func NormalizeBody(body []byte, ignorePaths []string) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil || decoder.More() {
+		return strings.TrimSpace(strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+
+	for _, path := range ignorePaths {
+		DeleteJSONPath(doc, path)
+	}
+
+	normalized, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return strings.TrimSpace(string(body))
+	}
+	return string(normalized)
+}
+
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var diff []DiffLine
+	for _, line := range oldLines[:prefix] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	diff = append(diff, diffMiddle(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return diff
+}
+
+func diffMiddle(a, b []string) []DiffLine {
+	var diff []DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+	return diff
+}
+
+type snapshotState struct {
+	previous string
+	hasPrev  bool
+	pinned   string
+	isPinned bool
+}
+
+type SnapshotStore struct {
+	mu     sync.Mutex
+	states map[string]*snapshotState
+}
+
+func NewSnapshotStore() *SnapshotStore {
+	return &SnapshotStore{
+		states: make(map[string]*snapshotState),
+	}
+}
+
+func (ss *SnapshotStore) state(name string) *snapshotState {
+	state, ok := ss.states[name]
+	if !ok {
+		state = &snapshotState{}
+		ss.states[name] = state
+	}
+	return state
+}
+
+func (ss *SnapshotStore) baseline(profile Profile, state *snapshotState) (string, bool, error) {
+	if state.isPinned {
+		return state.pinned, true, nil
+	}
+
+	if profile.Snapshot.Baseline != "" {
+		path, err := profile.resolvePath(profile.Snapshot.Baseline)
+		if err != nil {
+			return "", false, err
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return NormalizeBody(data, profile.Snapshot.IgnorePaths), true, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", false, err
+		}
+	}
+
+	return state.previous, state.hasPrev, nil
+}
+
+func (ss *SnapshotStore) Compare(profile Profile, body []byte, result *PingResult) error {
+	normalized := NormalizeBody(body, profile.Snapshot.IgnorePaths)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	state := ss.state(profile.Name)
+	baseline, ok, err := ss.baseline(profile, state)
+
+	state.previous = normalized
+	state.hasPrev = true
+
+	if err != nil {
+		return err
+	}
+	if ok && baseline != normalized {
+		result.Changed = true
+		result.Diff = DiffLines(baseline, normalized)
+	}
+	return nil
+}
+
+func (ss *SnapshotStore) Pin(profile Profile) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	state := ss.state(profile.Name)
+	if !state.hasPrev {
+		return fmt.Errorf("no response captured yet")
+	}
+
+	state.pinned = state.previous
+	state.isPinned = true
+
+	if profile.Snapshot != nil && profile.Snapshot.Baseline != "" {
+		path, err := profile.resolvePath(profile.Snapshot.Baseline)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(state.pinned+"\n"), 0644)
+	}
+	return nil
+}
+
+func (ss *SnapshotStore) IsPinned(profile Profile) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if state, ok := ss.states[profile.Name]; ok && state.isPinned {
+		return true
+	}
+	if profile.Snapshot != nil && profile.Snapshot.Baseline != "" {
+		path, err := profile.resolvePath(profile.Snapshot.Baseline)
+		if err != nil {
+			return false
+		}
+		_, err = os.Stat(path)
+		return err == nil
+	}
+	return false
+}
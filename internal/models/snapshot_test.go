@@ -0,0 +1,105 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeBody(t *testing.T) {
+	body := []byte(`{"b":1,"a":{"updated_at":"now","v":2},"items":[{"id":"x","n":1},{"id":"y","n":2}]}`)
+
+	normalized := NormalizeBody(body, []string{"a.updated_at", "items.*.id"})
+	assert.Equal(t, `{
+  "a": {
+    "v": 2
+  },
+  "b": 1,
+  "items": [
+    {
+      "n": 1
+    },
+    {
+      "n": 2
+    }
+  ]
+}`, normalized)
+
+	assert.Equal(t, "plain\ntext", NormalizeBody([]byte("plain\r\ntext\r\n"), nil))
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("a\nb\nc\nd", "a\nc\nd\ne")
+
+	assert.Equal(t, []DiffLine{
+		{Op: DiffEqual, Text: "a"},
+		{Op: DiffDelete, Text: "b"},
+		{Op: DiffEqual, Text: "c"},
+		{Op: DiffEqual, Text: "d"},
+		{Op: DiffInsert, Text: "e"},
+	}, diff)
+}
+
+func TestPingService_Snapshot(t *testing.T) {
+	body := `{"version":"1","generated_at":"t1"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ps := NewPingService()
+	profile := Profile{
+		Name:     "config",
+		BaseURL:  server.URL,
+		Snapshot: &SnapshotConfig{IgnorePaths: []string{"generated_at"}},
+	}
+
+	result := ps.Ping(profile)
+	assert.True(t, result.Success)
+	assert.False(t, result.Changed)
+
+	body = `{"version":"1","generated_at":"t2"}`
+	result = ps.Ping(profile)
+	assert.False(t, result.Changed)
+
+	require.NoError(t, ps.Snapshots().Pin(profile))
+	assert.True(t, ps.Snapshots().IsPinned(profile))
+
+	body = `{"version":"2","generated_at":"t3"}`
+	result = ps.Ping(profile)
+	assert.True(t, result.Success)
+	assert.True(t, result.Changed)
+	assert.Contains(t, result.Diff, DiffLine{Op: DiffDelete, Text: `  "version": "1"`})
+	assert.Contains(t, result.Diff, DiffLine{Op: DiffInsert, Text: `  "version": "2"`})
+
+	result = ps.Ping(profile)
+	assert.True(t, result.Changed, "pinned baseline should not move with each ping")
+}
+
+func TestSnapshotStore_BaselineFile(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	profile := Profile{Name: "config", Snapshot: &SnapshotConfig{Baseline: baseline}}
+
+	store := NewSnapshotStore()
+	assert.Error(t, store.Pin(profile))
+
+	var result PingResult
+	require.NoError(t, store.Compare(profile, []byte(`{"a":1}`), &result))
+	assert.False(t, result.Changed)
+	require.NoError(t, store.Pin(profile))
+
+	data, err := os.ReadFile(baseline)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"a": 1`)
+
+	fresh := NewSnapshotStore()
+	assert.True(t, fresh.IsPinned(profile))
+	result = PingResult{}
+	require.NoError(t, fresh.Compare(profile, []byte(`{"a":2}`), &result))
+	assert.True(t, result.Changed)
+}
@@ -0,0 +1,308 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+type ProfileConflict struct {
+	Name  string
+	Files []string
+}
+
+func (c ProfileConflict) Winner() string {
+	return c.Files[len(c.Files)-1]
+}
+
+func (c ProfileConflict) String() string {
+	return fmt.Sprintf("profile %q is defined in %s; using %s", c.Name, strings.Join(c.Files, ", "), c.Winner())
+}
+
+type profileFile struct {
+	path     string
+	include  []string
+	profiles []Profile
+	dirty    bool
+	sum      [sha256.Size]byte
+	version  int
+}
+
+func (f *profileFile) dir() string {
+	path, err := filepath.Abs(f.path)
+	if err != nil {
+		path = f.path
+	}
+	return filepath.Dir(path)
+}
+
+func (f *profileFile) replace(name string, profile Profile) {
+	for i := len(f.profiles) - 1; i >= 0; i-- {
+		if f.profiles[i].Name == name {
+			f.profiles[i] = profile
+			f.dirty = true
+			return
+		}
+	}
+}
+
+func (f *profileFile) remove(name string) {
+	profiles := f.profiles[:0]
+	for _, p := range f.profiles {
+		if p.Name == name {
+			f.dirty = true
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	f.profiles = profiles
+}
+
+func (f *profileFile) render(current []byte) ([]byte, error) {
+	format, err := FormatForPath(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []byte
+	if format == FormatYAML {
+		previous = current
+	}
+
+	profiles := f.profiles
+	if profiles == nil {
+		profiles = []Profile{}
+	}
+	return encodeDocument(format, profileDocument{Include: f.include, Profiles: profiles}, previous)
+}
+
+func (f *profileFile) save() error {
+	current, err := os.ReadFile(f.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exists := err == nil
+
+	data, err := f.render(current)
+	if err != nil {
+		return err
+	}
+
+	if exists && bytes.Equal(current, data) {
+		f.sum = sha256.Sum256(data)
+		return nil
+	}
+	if exists {
+		if err := rotateBackups(f.path, current, profileBackups); err != nil {
+			return fmt.Errorf("backup %s: %w", f.path, err)
+		}
+	}
+	if err := WriteFileAtomic(f.path, data, 0644); err != nil {
+		return err
+	}
+	f.sum = sha256.Sum256(data)
+	f.version = ConfigVersion
+	return nil
+}
+
+type profileLoader struct {
+	files []*profileFile
+	seen  map[string]bool
+}
+
+func loadProfileFiles(root string) ([]*profileFile, error) {
+	loader := &profileLoader{seen: make(map[string]bool)}
+	if err := loader.load(root, true); err != nil {
+		return nil, err
+	}
+	return loader.files, nil
+}
+
+func (l *profileLoader) load(path string, root bool) error {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+	if l.seen[key] {
+		return nil
+	}
+	l.seen[key] = true
+
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+
+	file := &profileFile{path: path, version: ConfigVersion}
+	data, err := os.ReadFile(path)
+	if root && os.IsNotExist(err) {
+		l.files = append(l.files, file)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	doc, version, err := decodeDocument(format, data)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	file.sum = sha256.Sum256(data)
+	file.version = version
+	file.include = doc.Include
+	file.profiles = doc.Profiles
+	for i := range file.profiles {
+		file.profiles[i].BaseDir = file.dir()
+	}
+
+	for _, include := range doc.Include {
+		pattern := include
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: include %q: %w", path, include, err)
+		}
+		if len(matches) == 0 && !hasGlobMeta(include) {
+			return fmt.Errorf("%s: include %q: file not found", path, include)
+		}
+
+		for _, match := range matches {
+			if err := l.load(match, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	l.files = append(l.files, file)
+	return nil
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+func (pm *ProfilesManager) merge() {
+	pm.profiles = []Profile{}
+	pm.sources = make(map[string]*profileFile)
+	pm.conflicts = nil
+
+	index := make(map[string]int)
+	definedIn := make(map[string][]string)
+	for _, file := range pm.files {
+		for _, profile := range file.profiles {
+			definedIn[profile.Name] = append(definedIn[profile.Name], file.path)
+			pm.sources[profile.Name] = file
+			if i, ok := index[profile.Name]; ok {
+				pm.profiles[i] = profile
+				continue
+			}
+			index[profile.Name] = len(pm.profiles)
+			pm.profiles = append(pm.profiles, profile)
+		}
+	}
+
+	for _, profile := range pm.profiles {
+		if files := definedIn[profile.Name]; len(files) > 1 {
+			pm.conflicts = append(pm.conflicts, ProfileConflict{Name: profile.Name, Files: files})
+		}
+	}
+}
+
+func sameFiles(a, b []*profileFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].path != b[i].path || a[i].sum != b[i].sum {
+			return false
+		}
+	}
+	return true
+}
+
+func validateFiles(files []*profileFile) error {
+	var errs []error
+	for _, file := range files {
+		seen := make(map[string]bool)
+		for i, profile := range file.profiles {
+			err := profile.Validate()
+			if err == nil && seen[profile.Name] {
+				err = fmt.Errorf("duplicate profile name")
+			}
+			seen[profile.Name] = true
+			if err != nil {
+				errs = append(errs, &InvalidProfileError{File: file.path, Index: i, Name: profile.Name, Err: err})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (pm *ProfilesManager) rootFile() *profileFile {
+	if pm.root == nil {
+		pm.root = &profileFile{path: pm.filePath, dirty: true}
+		pm.files = append(pm.files, pm.root)
+	}
+	if pm.root.path != pm.filePath {
+		pm.root.path = pm.filePath
+		pm.root.dirty = true
+	}
+	return pm.root
+}
+
+func (pm *ProfilesManager) Conflicts() []ProfileConflict {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.conflicts
+}
+
+func (pm *ProfilesManager) Source(name string) string {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if file, ok := pm.sources[name]; ok {
+		return file.path
+	}
+	return ""
+}
+
+func (pm *ProfilesManager) Files() []string {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	files := make([]string, 0, len(pm.files))
+	for _, file := range pm.files {
+		files = append(files, file.path)
+	}
+	return files
+}
+
+func DiffProfiles(before, after []Profile) (changed []Profile, removed []string) {
+	previous := make(map[string]Profile, len(before))
+	for _, profile := range before {
+		previous[profile.Name] = profile
+	}
+
+	for _, profile := range after {
+		old, ok := previous[profile.Name]
+		if !ok || !reflect.DeepEqual(old, profile) {
+			changed = append(changed, profile)
+		}
+		delete(previous, profile.Name)
+	}
+	for _, profile := range before {
+		if _, ok := previous[profile.Name]; ok {
+			removed = append(removed, profile.Name)
+		}
+	}
+	return changed, removed
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestProfilesManager_Includes(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "route-keeper.yaml")
+	writeFile(t, root, `include:
+  - shared.json
+  - conf.d/*.toml
+profiles:
+  - name: api
+    base_url: https://override.example.com
+    interval: 1
+`)
+	writeFile(t, filepath.Join(dir, "shared.json"), `[
+  {"name": "api", "base_url": "https://api.example.com", "interval": 5},
+  {"name": "web", "base_url": "https://example.com", "interval": 5}
+]`)
+	writeFile(t, filepath.Join(dir, "conf.d", "10-db.toml"), `
+[[profiles]]
+name = "db"
+type = "tcp"
+base_url = "db:5432"
+interval = 5
+`)
+	writeFile(t, filepath.Join(dir, "conf.d", "20-db.toml"), `
+[[profiles]]
+name = "db"
+type = "tcp"
+base_url = "replica:5432"
+interval = 5
+`)
+
+	pm := NewProfilesManagerForFile(root)
+	require.NoError(t, pm.LoadProfiles())
+
+	profiles := pm.GetProfiles()
+	require.Len(t, profiles, 3)
+	assert.Equal(t, "api", profiles[0].Name)
+	assert.Equal(t, "https://override.example.com", profiles[0].BaseURL)
+	assert.Equal(t, "web", profiles[1].Name)
+	assert.Equal(t, "replica:5432", profiles[2].BaseURL)
+
+	assert.Equal(t, root, pm.Source("api"))
+	assert.Equal(t, filepath.Join(dir, "shared.json"), pm.Source("web"))
+	assert.Equal(t, filepath.Join(dir, "conf.d", "20-db.toml"), pm.Source("db"))
+	assert.Len(t, pm.Files(), 4)
+
+	conflicts := pm.Conflicts()
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "api", conflicts[0].Name)
+	assert.Equal(t, root, conflicts[0].Winner())
+	assert.Contains(t, conflicts[1].String(), `profile "db" is defined in `)
+	assert.Contains(t, conflicts[1].String(), "using "+filepath.Join(dir, "conf.d", "20-db.toml"))
+}
+
+func TestProfilesManager_RelativePathsFollowSourceFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1, "name": "houston"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "profiles.json")
+	writeFile(t, root, `{"include": ["services/*.yaml"], "profiles": []}`)
+	writeFile(t, filepath.Join(dir, "services", "users.yaml"), `profiles:
+  - name: users
+    base_url: `+server.URL+`
+    interval: 5
+    schema: schemas/user.schema.json
+    snapshot:
+      baseline: baselines/users.json
+`)
+	writeFile(t, filepath.Join(dir, "services", "schemas", "user.schema.json"), userSchema)
+
+	t.Chdir(t.TempDir())
+	pm := NewProfilesManagerForFile(root)
+	require.NoError(t, pm.LoadProfiles())
+	profiles := pm.GetProfiles()
+	require.Len(t, profiles, 1)
+
+	result := NewPingService().Ping(profiles[0])
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+
+	store := NewSnapshotStore()
+	require.NoError(t, store.Compare(profiles[0], []byte(`{"id": 1}`), &PingResult{}))
+	require.NoError(t, store.Pin(profiles[0]))
+	assert.FileExists(t, filepath.Join(dir, "services", "baselines", "users.json"))
+
+	require.NoError(t, pm.AddProfile(Profile{Name: "orders", BaseURL: server.URL, Interval: 5, Schema: "orders.schema.json"}))
+	profiles = pm.GetProfiles()
+	require.Len(t, profiles, 2)
+	assert.Equal(t, dir, profiles[1].BaseDir)
+
+	data, err := os.ReadFile(root)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), dir)
+}
+
+func TestProfilesManager_WritesBackToSource(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "profiles.json")
+	shared := filepath.Join(dir, "conf.d", "shared.yaml")
+	writeFile(t, root, `{"include": ["conf.d/*.yaml"], "profiles": []}`)
+	writeFile(t, shared, `# Shared monitors
+- name: web # landing page
+  base_url: https://example.com
+  interval: 5
+- name: docs
+  base_url: https://docs.example.com
+  interval: 5
+`)
+
+	pm := NewProfilesManagerForFile(root)
+	require.NoError(t, pm.LoadProfiles())
+
+	web := pm.GetProfiles()[0]
+	web.Interval = 10
+	require.NoError(t, pm.AddProfile(web))
+	require.NoError(t, pm.AddProfile(Profile{Name: "api", BaseURL: "https://api.example.com", Interval: 1}))
+	require.NoError(t, pm.UpdateProfile("docs", Profile{Name: "handbook", BaseURL: "https://docs.example.com", Interval: 5}))
+
+	data, err := os.ReadFile(shared)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Shared monitors")
+	assert.Contains(t, string(data), "name: web # landing page")
+	assert.Contains(t, string(data), "interval: 10")
+	assert.Contains(t, string(data), "name: handbook")
+	assert.NotContains(t, string(data), "api.example.com")
+
+	reloaded := NewProfilesManagerForFile(root)
+	require.NoError(t, reloaded.LoadProfiles())
+	require.Len(t, reloaded.GetProfiles(), 3)
+	assert.Equal(t, root, reloaded.Source("api"))
+	assert.Equal(t, shared, reloaded.Source("handbook"))
+
+	require.NoError(t, reloaded.DeleteProfile("web"))
+	data, err = os.ReadFile(shared)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "name: web")
+}
+
+func TestProfilesManager_DeleteRemovesShadowedCopies(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "profiles.json")
+	writeFile(t, root, `{"include": ["base.json"], "profiles": [{"name": "api", "base_url": "https://b", "interval": 1}]}`)
+	writeFile(t, filepath.Join(dir, "base.json"), `[{"name": "api", "base_url": "https://a", "interval": 1}]`)
+
+	pm := NewProfilesManagerForFile(root)
+	require.NoError(t, pm.LoadProfiles())
+	require.NoError(t, pm.DeleteProfile("api"))
+
+	reloaded := NewProfilesManagerForFile(root)
+	require.NoError(t, reloaded.LoadProfiles())
+	assert.Empty(t, reloaded.GetProfiles())
+}
+
+func TestProfilesManager_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "profiles.yaml")
+
+	writeFile(t, root, "include: [missing.yaml]\n")
+	assert.ErrorContains(t, NewProfilesManagerForFile(root).LoadProfiles(), `include "missing.yaml": file not found`)
+
+	writeFile(t, root, "include: [conf.d/*.yaml]\n")
+	require.NoError(t, NewProfilesManagerForFile(root).LoadProfiles())
+
+	writeFile(t, root, "include: [other.yaml]\n")
+	writeFile(t, filepath.Join(dir, "other.yaml"), "include: [profiles.yaml]\nprofiles:\n  - name: api\n    base_url: https://api.example.com\n")
+	pm := NewProfilesManagerForFile(root)
+	require.NoError(t, pm.LoadProfiles())
+	assert.Len(t, pm.GetProfiles(), 1)
+}
+
+func TestDiffProfiles(t *testing.T) {
+	before := []Profile{{Name: "api", Interval: 1}, {Name: "web", Interval: 5}, {Name: "db", Interval: 5}}
+	after := []Profile{{Name: "api", Interval: 1}, {Name: "web", Interval: 10}, {Name: "docs", Interval: 5}}
+
+	changed, removed := DiffProfiles(before, after)
+	require.Len(t, changed, 2)
+	assert.Equal(t, "web", changed[0].Name)
+	assert.Equal(t, "docs", changed[1].Name)
+	assert.Equal(t, []string{"db"}, removed)
+}
+
+func TestProfilesManager_RenameOntoExistingProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	pm := NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+	require.NoError(t, pm.AddProfile(Profile{Name: "api", BaseURL: "https://api.example.com", Interval: 5}))
+
+	other := NewProfilesManagerForFile(path)
+	require.NoError(t, other.LoadProfiles())
+	require.NoError(t, other.AddProfile(Profile{Name: "web", BaseURL: "https://web.example.com", Interval: 5}))
+
+	err := pm.UpdateProfile("api", Profile{Name: "web", BaseURL: "https://api.example.com", Interval: 5})
+	assert.EqualError(t, err, `profile "web" already exists`)
+
+	reloaded := NewProfilesManagerForFile(path)
+	require.NoError(t, reloaded.LoadProfiles())
+	profiles := reloaded.GetProfiles()
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "https://web.example.com", profiles[1].BaseURL)
+}
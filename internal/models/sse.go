@@ -0,0 +1,186 @@
+package models
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const ProfileTypeSSE = "sse"
+
+type SSEConfig struct {
+	Events         int    `json:"events,omitempty"`
+	Event          string `json:"event,omitempty"`
+	Match          string `json:"match,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+type sseMatcher struct {
+	event string
+	match *regexp.Regexp
+}
+
+func (m sseMatcher) matches(event SSEEvent) bool {
+	if m.event != "" && event.Event != m.event {
+		return false
+	}
+	if m.match != nil && !m.match.MatchString(event.Data) {
+		return false
+	}
+	return true
+}
+
+func readSSEEvents(scanner *bufio.Scanner, emit func(SSEEvent) bool) error {
+	var event SSEEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				event.Data = strings.Join(data, "\n")
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				if emit(event) {
+					return nil
+				}
+			}
+			event, data = SSEEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("stream closed")
+}
+
+func (ps *PingService) pingSSE(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	config := SSEConfig{}
+	if profile.SSE != nil {
+		config = *profile.SSE
+	}
+	wanted := config.Events
+	if wanted <= 0 {
+		wanted = 1
+	}
+
+	matcher := sseMatcher{event: config.Event}
+	if config.Match != "" {
+		re, err := regexp.Compile(config.Match)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid match pattern: %w", err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		matcher.match = re
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(config.TimeoutSeconds))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", profile.GetFullURL(), nil)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	for k, v := range profile.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if err := ps.authorize(req, profile); err != nil {
+		result.AuthError = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer resp.Body.Close()
+
+	ps.checkUnauthorized(resp, profile)
+	result.StatusCode = resp.StatusCode
+	result.Timings = append(result.Timings, Timing{Name: "connect", Duration: time.Since(start)})
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Duration = time.Since(start)
+		return result
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		result.Error = fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	received, matched := 0, 0
+	var last SSEEvent
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxBodySize)
+
+	err = readSSEEvents(scanner, func(event SSEEvent) bool {
+		received++
+		if received == 1 {
+			result.Timings = append(result.Timings, Timing{Name: "first event", Duration: time.Since(start)})
+		}
+		if !matcher.matches(event) {
+			return false
+		}
+		matched++
+		last = event
+		return matched >= wanted
+	})
+	result.Duration = time.Since(start)
+
+	if err == nil {
+		result.Success = true
+		result.Detail = firstLine(last.Event + ": " + last.Data)
+		return result
+	}
+
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %d matching of %d received events", matched, received)
+	} else {
+		err = fmt.Errorf("%w after %d matching of %d received events", err, matched, received)
+	}
+	result.Error = err
+	return result
+}
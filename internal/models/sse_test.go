@@ -0,0 +1,88 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSSEServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			w.Write([]byte("hello"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		flusher := w.(http.Flusher)
+
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"n\":1}\n\n")
+		fmt.Fprint(w, "event: notification\nid: 2\ndata: {\"type\":\"order\",\n")
+		fmt.Fprint(w, "data: \"n\":2}\n\n")
+		fmt.Fprint(w, "event: notification\ndata: {\"type\":\"invoice\",\"n\":3}\n\n")
+		flusher.Flush()
+
+		if r.URL.Path == "/hang" {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReadSSEEvents(t *testing.T) {
+	stream := "event: a\ndata: one\ndata: two\n\n:comment\ndata:three\n\n"
+
+	var events []SSEEvent
+	err := readSSEEvents(bufio.NewScanner(strings.NewReader(stream)), func(event SSEEvent) bool {
+		events = append(events, event)
+		return false
+	})
+
+	assert.EqualError(t, err, "stream closed")
+	assert.Equal(t, []SSEEvent{
+		{Event: "a", Data: "one\ntwo"},
+		{Event: "message", Data: "three"},
+	}, events)
+}
+
+func TestPingService_SSE(t *testing.T) {
+	server := newSSEServer(t)
+	ps := NewPingService()
+	profile := func(route string, config *SSEConfig) Profile {
+		return Profile{Name: "notifications", Type: ProfileTypeSSE, BaseURL: server.URL, Route: route, SSE: config}
+	}
+
+	result := ps.Ping(profile("/stream", nil))
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, `message: {"n":1}`, result.Detail)
+	require.Len(t, result.Timings, 2)
+	assert.Equal(t, "first event", result.Timings[1].Name)
+
+	result = ps.Ping(profile("/stream", &SSEConfig{Event: "notification", Match: `"type":"invoice"`}))
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+
+	result = ps.Ping(profile("/stream", &SSEConfig{Events: 3}))
+	assert.True(t, result.Success)
+
+	result = ps.Ping(profile("/stream", &SSEConfig{Events: 4}))
+	assert.False(t, result.Success)
+	assert.EqualError(t, result.Error, "stream closed after 3 matching of 3 received events")
+
+	result = ps.Ping(profile("/hang", &SSEConfig{Event: "heartbeat", TimeoutSeconds: 1}))
+	assert.False(t, result.Success)
+	assert.EqualError(t, result.Error, "timed out after 0 matching of 3 received events")
+
+	result = ps.Ping(profile("/plain", nil))
+	assert.False(t, result.Success)
+	assert.ErrorContains(t, result.Error, "unexpected content type")
+}
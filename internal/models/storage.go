@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const profileBackups = 3
+
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	path, err := resolveSymlinks(path)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, data, fileMode(path, perm))
+}
+
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func resolveSymlinks(path string) (string, error) {
+	for range 255 {
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return path, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return path, nil
+		}
+
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = target
+	}
+	return "", fmt.Errorf("%s: too many levels of symbolic links", path)
+}
+
+func fileMode(path string, fallback os.FileMode) os.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return fallback
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+func rotateBackups(path string, current []byte, keep int) error {
+	perm := fileMode(path, 0644)
+	for i := keep - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err := os.Chmod(backupPath(path, i+1), perm); err != nil {
+			return err
+		}
+	}
+	return replaceFile(backupPath(path, 1), current, perm)
+}
+
+type fileLock struct {
+	file *os.File
+}
+
+func acquireLock(path string, exclusive bool) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return &fileLock{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file, exclusive); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	return &fileLock{file: file}, nil
+}
+
+func (l *fileLock) release() {
+	if l.file == nil {
+		return
+	}
+	unlockFile(l.file)
+	l.file.Close()
+}
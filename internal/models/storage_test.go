@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilesManager_AtomicWritesAndBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "profiles.json")
+	pm := NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, pm.AddProfile(Profile{Name: fmt.Sprintf("p%d", i), BaseURL: "https://example.com", Interval: i}))
+	}
+
+	for n := 1; n <= profileBackups; n++ {
+		data, err := os.ReadFile(backupPath(path, n))
+		require.NoError(t, err)
+		doc, _, err := decodeDocument(FormatJSON, data)
+		require.NoError(t, err)
+		assert.Len(t, doc.Profiles, 5-n, "backup %d", n)
+	}
+	assert.NoFileExists(t, backupPath(path, profileBackups+1))
+
+	before, err := os.ReadFile(backupPath(path, 1))
+	require.NoError(t, err)
+	require.NoError(t, pm.SaveProfiles())
+	after, err := os.ReadFile(backupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".tmp-")
+	}
+}
+
+func TestProfilesManager_PicksUpExternalChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	first := NewProfilesManagerForFile(path)
+	second := NewProfilesManagerForFile(path)
+	require.NoError(t, first.LoadProfiles())
+	require.NoError(t, second.LoadProfiles())
+
+	require.NoError(t, first.AddProfile(Profile{Name: "a", BaseURL: "https://a.example.com", Interval: 5}))
+	require.NoError(t, second.AddProfile(Profile{Name: "b", BaseURL: "https://b.example.com", Interval: 5}))
+
+	reloaded := NewProfilesManagerForFile(path)
+	require.NoError(t, reloaded.LoadProfiles())
+	require.Len(t, reloaded.GetProfiles(), 2)
+
+	changed, err := first.ReloadIfChanged()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, first.GetProfiles(), 2)
+
+	changed, err = first.ReloadIfChanged()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "a", "base_url": `), 0644))
+	err = first.DeleteProfile("a")
+	assert.ErrorContains(t, err, "changed on disk and could not be reloaded")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name": "a", "base_url": `, string(data))
+}
+
+func TestProfilesManager_ConcurrentAccess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	managers := []*ProfilesManager{NewProfilesManagerForFile(path), NewProfilesManagerForFile(path)}
+	for _, pm := range managers {
+		require.NoError(t, pm.LoadProfiles())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pm := managers[i%len(managers)]
+			assert.NoError(t, pm.AddProfile(Profile{Name: fmt.Sprintf("p%d", i), BaseURL: "https://example.com", Interval: 5}))
+			pm.GetProfiles()
+		}(i)
+	}
+	wg.Wait()
+
+	reloaded := NewProfilesManagerForFile(path)
+	require.NoError(t, reloaded.LoadProfiles())
+	assert.Len(t, reloaded.GetProfiles(), 20)
+}
+
+func TestProfilesManager_KeepsFileModeAndSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and symlinks need a Unix filesystem")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "profiles.json")
+	link := filepath.Join(dir, "profiles.json")
+	writeFile(t, target, `[{"name": "api", "base_url": "https://api.example.com", "interval": 5}]`)
+	require.NoError(t, os.Chmod(target, 0600))
+	require.NoError(t, os.Symlink(filepath.Join("dotfiles", "profiles.json"), link))
+
+	pm := NewProfilesManagerForFile(link)
+	require.NoError(t, pm.LoadProfiles())
+	require.NoError(t, pm.AddProfile(Profile{Name: "web", BaseURL: "https://example.com", Interval: 5}))
+	require.NoError(t, pm.AddProfile(Profile{Name: "db", Type: ProfileTypeTCP, BaseURL: "db:5432", Interval: 5}))
+
+	info, err := os.Lstat(link)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSymlink, "link was replaced by a regular file")
+
+	info, err = os.Stat(target)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reloaded := NewProfilesManagerForFile(target)
+	require.NoError(t, reloaded.LoadProfiles())
+	assert.Len(t, reloaded.GetProfiles(), 3)
+
+	for n := 1; n <= 2; n++ {
+		info, err := os.Stat(backupPath(link, n))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "backup %d", n)
+	}
+}
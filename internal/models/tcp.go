@@ -0,0 +1,121 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const ProfileTypeTCP = "tcp"
+
+const maxBannerSize = 64 * 1024
+
+type TCPConfig struct {
+	Send           string `json:"send,omitempty"`
+	Expect         string `json:"expect,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+type TCPChecker struct{}
+
+func tcpAddress(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(baseURL, "tcp://"), "/")
+}
+
+func checkTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (c *TCPChecker) Check(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	config := TCPConfig{}
+	if profile.TCP != nil {
+		config = *profile.TCP
+	}
+	timeout := checkTimeout(config.TimeoutSeconds)
+	deadline := start.Add(timeout)
+
+	var expect *regexp.Regexp
+	if config.Expect != "" {
+		re, err := regexp.Compile(config.Expect)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid expect pattern: %w", err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		expect = re
+	}
+
+	conn, err := net.DialTimeout("tcp", tcpAddress(profile.BaseURL), timeout)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	if config.Send != "" {
+		if _, err := conn.Write([]byte(config.Send)); err != nil {
+			result.Error = fmt.Errorf("send: %w", err)
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+
+	if expect == nil {
+		result.Success = true
+		result.Detail = "connected"
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var received bytes.Buffer
+	buf := make([]byte, 4096)
+	for received.Len() < maxBannerSize {
+		n, err := conn.Read(buf)
+		received.Write(buf[:n])
+
+		if match := expect.Find(received.Bytes()); match != nil {
+			result.Success = true
+			result.Detail = strings.TrimSpace(string(match))
+			result.Duration = time.Since(start)
+			return result
+		}
+
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				err = fmt.Errorf("timed out waiting for %q", config.Expect)
+			} else {
+				err = fmt.Errorf("reply did not match %q: %w", config.Expect, err)
+			}
+			result.Error = err
+			result.Detail = firstLine(received.String())
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+
+	result.Error = fmt.Errorf("reply did not match %q", config.Expect)
+	result.Detail = firstLine(received.String())
+	result.Duration = time.Since(start)
+	return result
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTCPServer(t *testing.T, handle func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestTCPChecker(t *testing.T) {
+	addr := newTCPServer(t, func(conn net.Conn) {
+		conn.Write([]byte("220 mail.example.com ESMTP ready\r\n"))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err == nil && line == "PING\r\n" {
+			conn.Write([]byte("+PONG\r\n"))
+		}
+	})
+
+	ps := NewPingService()
+
+	result := ps.Ping(Profile{Name: "connect", Type: ProfileTypeTCP, BaseURL: "tcp://" + addr})
+	assert.True(t, result.Success)
+	assert.Equal(t, "connected", result.Detail)
+
+	result = ps.Ping(Profile{Name: "banner", Type: ProfileTypeTCP, BaseURL: addr, TCP: &TCPConfig{Expect: `^220 .*ESMTP`}})
+	assert.True(t, result.Success)
+	assert.Equal(t, "220 mail.example.com ESMTP", result.Detail)
+
+	result = ps.Ping(Profile{Name: "pong", Type: ProfileTypeTCP, BaseURL: addr, TCP: &TCPConfig{Send: "PING\r\n", Expect: `\+PONG`}})
+	assert.True(t, result.Success)
+
+	result = ps.Ping(Profile{Name: "mismatch", Type: ProfileTypeTCP, BaseURL: addr, TCP: &TCPConfig{Expect: "SSH-2.0", TimeoutSeconds: 1}})
+	assert.False(t, result.Success)
+	assert.Error(t, result.Error)
+	assert.Equal(t, "220 mail.example.com ESMTP ready", result.Detail)
+
+	result = ps.Ping(Profile{Name: "bad pattern", Type: ProfileTypeTCP, BaseURL: addr, TCP: &TCPConfig{Expect: "("}})
+	assert.ErrorContains(t, result.Error, "invalid expect pattern")
+}
+
+func TestTCPChecker_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	start := time.Now()
+	result := NewPingService().Ping(Profile{Name: "closed", Type: ProfileTypeTCP, BaseURL: addr})
+	assert.False(t, result.Success)
+	assert.Error(t, result.Error)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,248 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+const MaxInterval = 1440
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, err := range v {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v ValidationErrors) Field(name string) string {
+	for _, err := range v {
+		if err.Field == name {
+			return err.Message
+		}
+	}
+	return ""
+}
+
+type InvalidProfileError struct {
+	File  string
+	Index int
+	Name  string
+	Err   error
+}
+
+func (e *InvalidProfileError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: profile #%d: %v", e.File, e.Index+1, e.Err)
+	}
+	return fmt.Sprintf("%s: profile #%d (%q): %v", e.File, e.Index+1, e.Name, e.Err)
+}
+
+func (e *InvalidProfileError) Unwrap() error {
+	return e.Err
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *Profile) Validate() error {
+	v := &validator{}
+
+	if strings.TrimSpace(p.Name) == "" {
+		v.add("name", "is required")
+	}
+	p.validateTarget(v)
+
+	for key := range p.Params {
+		if strings.TrimSpace(key) == "" {
+			v.add("params", "parameter names cannot be empty")
+			break
+		}
+	}
+	validateHeaders(v, "headers", p.Headers)
+
+	if p.Interval < 0 || p.Interval > MaxInterval {
+		v.add("interval", "must be between 1 and %d minutes, or 0 for the %d-minute default", MaxInterval, DefaultIntervalMinutes)
+	}
+
+	if p.Group != "" && slices.ContainsFunc(strings.Split(p.Group, GroupSeparator), func(folder string) bool {
+		return strings.TrimSpace(folder) == ""
+	}) {
+		v.add("group", "%q has an empty folder name", p.Group)
+	}
+	for _, tag := range p.Tags {
+		if tag == "" || strings.ContainsAny(tag, " \t,#") {
+			v.add("tags", "%q is not a valid tag", tag)
+			break
+		}
+	}
+
+	if p.Kind() == ProfileTypeScenario {
+		p.validateSteps(v)
+	}
+
+	if err := p.Schedule.Validate(); err != nil {
+		v.add("schedule", "%v", err)
+	}
+	for i, window := range p.Maintenance {
+		if err := window.Validate(); err != nil {
+			v.add(fmt.Sprintf("maintenance[%d]", i), "%v", err)
+		}
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (p *Profile) validateTarget(v *validator) {
+	if strings.TrimSpace(p.BaseURL) == "" {
+		v.add("base_url", "is required")
+		return
+	}
+
+	switch p.Kind() {
+	case ProfileTypeHTTP, ProfileTypeScenario, ProfileTypeGraphQL, ProfileTypeSSE:
+		validateURL(v, "base_url", p.BaseURL, "http", "https")
+	case ProfileTypeWebSocket:
+		validateURL(v, "base_url", p.BaseURL, "ws", "wss")
+	case ProfileTypeTCP:
+		validateHostPort(v, tcpAddress(p.BaseURL))
+	case ProfileTypeGRPC:
+		target, _ := grpcTarget(p.BaseURL)
+		validateHostPort(v, target)
+	case ProfileTypeDNS:
+		if host := strings.TrimPrefix(p.BaseURL, "dns://"); strings.ContainsAny(host, " /:") {
+			v.add("base_url", "%q is not a valid hostname", host)
+		}
+	}
+
+	if p.UsesURL() && p.Route != "" {
+		if _, err := url.Parse(p.Route); err != nil || strings.ContainsAny(p.Route, " \t") {
+			v.add("route", "%q is not a valid path", p.Route)
+		}
+	}
+}
+
+func validateURL(v *validator, field, raw string, schemes ...string) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		v.add(field, "%q is not a valid URL", raw)
+		return
+	}
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		v.add(field, "must start with %s://", strings.Join(schemes, ":// or "))
+		return
+	}
+	if u.Host == "" {
+		v.add(field, "is missing a host")
+	}
+}
+
+func validateHostPort(v *validator, address string) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		v.add("base_url", "must be host:port")
+		return
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		v.add("base_url", "port %q is not between 1 and 65535", port)
+	}
+}
+
+func validateHeaders(v *validator, field string, headers map[string]string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !httpguts.ValidHeaderFieldName(name) {
+			v.add(field, "%q is not a valid header name", name)
+		}
+	}
+}
+
+func (p *Profile) validateSteps(v *validator) {
+	if len(p.Steps) == 0 {
+		v.add("steps", "a scenario needs at least one step")
+		return
+	}
+
+	defined := make(map[string]bool)
+	for i, step := range p.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+
+		for _, name := range step.references() {
+			if !defined[name] {
+				v.add(field, "references undefined variable %q", name)
+			}
+		}
+		validateHeaders(v, field, step.Headers)
+
+		for _, extraction := range step.Extract {
+			if extraction.Var == "" {
+				v.add(field, "extraction is missing a var")
+			}
+			switch extraction.From {
+			case ExtractJSON, ExtractHeader:
+			case ExtractRegex:
+				if _, err := regexp.Compile(extraction.Path); err != nil {
+					v.add(field, "invalid regex for %q: %v", extraction.Var, err)
+				}
+			default:
+				v.add(field, "unsupported extraction source %q", extraction.From)
+			}
+			defined[extraction.Var] = true
+		}
+	}
+}
+
+func (s *ScenarioStep) references() []string {
+	texts := []string{s.Route, s.Body}
+	for _, value := range s.Params {
+		texts = append(texts, value)
+	}
+	for _, value := range s.Headers {
+		texts = append(texts, value)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, text := range texts {
+		for _, match := range templateVarPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
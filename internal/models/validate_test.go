@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		field   string
+		message string
+	}{
+		{"valid http", Profile{Name: "api", BaseURL: "https://api.example.com", Route: "/health", Interval: 5}, "", ""},
+		{"default interval", Profile{Name: "api", BaseURL: "http://localhost:8080"}, "", ""},
+		{"missing name", Profile{BaseURL: "https://api.example.com"}, "name", "is required"},
+		{"missing base url", Profile{Name: "api"}, "base_url", "is required"},
+		{"missing scheme", Profile{Name: "api", BaseURL: "api.example.com"}, "base_url", "must start with http:// or https://"},
+		{"missing host", Profile{Name: "api", BaseURL: "https://"}, "base_url", "is missing a host"},
+		{"websocket scheme", Profile{Name: "ws", Type: ProfileTypeWebSocket, BaseURL: "https://example.com"}, "base_url", "must start with ws:// or wss://"},
+		{"tcp address", Profile{Name: "db", Type: ProfileTypeTCP, BaseURL: "tcp://db"}, "base_url", "must be host:port"},
+		{"tcp port", Profile{Name: "db", Type: ProfileTypeTCP, BaseURL: "db:99999"}, "base_url", `port "99999" is not between 1 and 65535`},
+		{"grpc target", Profile{Name: "rpc", Type: ProfileTypeGRPC, BaseURL: "grpcs://rpc.example.com:443"}, "", ""},
+		{"dns host", Profile{Name: "dns", Type: ProfileTypeDNS, BaseURL: "example.com/x"}, "base_url", `"example.com/x" is not a valid hostname`},
+		{"route", Profile{Name: "api", BaseURL: "https://api.example.com", Route: "/he alth"}, "route", `"/he alth" is not a valid path`},
+		{"header name", Profile{Name: "api", BaseURL: "https://api.example.com", Headers: map[string]string{"Bad Header": "x"}}, "headers", `"Bad Header" is not a valid header name`},
+		{"empty param", Profile{Name: "api", BaseURL: "https://api.example.com", Params: map[string]string{"": "x"}}, "params", "parameter names cannot be empty"},
+		{"negative interval", Profile{Name: "api", BaseURL: "https://api.example.com", Interval: -1}, "interval", "must be between 1 and 1440 minutes, or 0 for the 5-minute default"},
+		{"huge interval", Profile{Name: "api", BaseURL: "https://api.example.com", Interval: 5000}, "interval", "must be between 1 and 1440 minutes, or 0 for the 5-minute default"},
+		{"group", Profile{Name: "api", BaseURL: "https://api.example.com", Group: "payments//eu"}, "group", `"payments//eu" has an empty folder name`},
+		{"tag", Profile{Name: "api", BaseURL: "https://api.example.com", Tags: []string{"prod", "two words"}}, "tags", `"two words" is not a valid tag`},
+		{"schedule", Profile{Name: "api", BaseURL: "https://api.example.com", Schedule: &ScheduleConfig{Cron: "nope"}}, "schedule", ""},
+		{"empty scenario", Profile{Name: "flow", Type: ProfileTypeScenario, BaseURL: "https://api.example.com"}, "steps", "a scenario needs at least one step"},
+		{"undefined variable", Profile{
+			Name:    "flow",
+			Type:    ProfileTypeScenario,
+			BaseURL: "https://api.example.com",
+			Steps: []ScenarioStep{
+				{Name: "login", Route: "/login", Extract: []Extraction{{Var: "token", From: ExtractJSON, Path: "$.token"}}},
+				{Name: "me", Route: "/users/{{user_id}}", Headers: map[string]string{"Authorization": "Bearer {{ token }}"}},
+			},
+		}, "steps[1]", `references undefined variable "user_id"`},
+		{"variable used before extraction", Profile{
+			Name:    "flow",
+			Type:    ProfileTypeScenario,
+			BaseURL: "https://api.example.com",
+			Steps: []ScenarioStep{
+				{Name: "me", Route: "/me", Body: `{"token":"{{token}}"}`},
+				{Name: "login", Route: "/login", Extract: []Extraction{{Var: "token", From: "cookie"}}},
+			},
+		}, "steps[0]", `references undefined variable "token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errs ValidationErrors
+			require.True(t, errors.As(err, &errs), "expected validation errors, got %v", err)
+			assert.NotEmpty(t, errs.Field(tt.field), err.Error())
+			if tt.message != "" {
+				assert.Equal(t, tt.message, errs.Field(tt.field))
+			}
+		})
+	}
+}
+
+func TestProfilesManager_LoadReportsInvalidProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {"name": "api", "base_url": "https://api.example.com", "interval": 5},
+  {"name": "broken", "base_url": "api.example.com", "interval": 5},
+  {"name": "api", "base_url": "https://api.example.com", "interval": 5}
+]`), 0644))
+
+	pm := NewProfilesManagerForFile(path)
+	err := pm.LoadProfiles()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `profiles.json: profile #2 ("broken"): base_url: must start with http:// or https://`)
+	assert.Contains(t, err.Error(), `profiles.json: profile #3 ("api"): duplicate profile name`)
+
+	var invalid *InvalidProfileError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, 1, invalid.Index)
+	assert.Len(t, pm.GetProfiles(), 2)
+}
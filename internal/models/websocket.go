@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const ProfileTypeWebSocket = "websocket"
+
+type WebSocketConfig struct {
+	Send           string   `json:"send,omitempty"`
+	Expect         string   `json:"expect,omitempty"`
+	Subprotocols   []string `json:"subprotocols,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+type WebSocketChecker struct{}
+
+func (c *WebSocketChecker) Check(profile Profile) PingResult {
+	start := time.Now()
+	result := PingResult{
+		Timestamp: start,
+		Success:   false,
+	}
+
+	config := WebSocketConfig{}
+	if profile.WebSocket != nil {
+		config = *profile.WebSocket
+	}
+	timeout := checkTimeout(config.TimeoutSeconds)
+
+	var expect *regexp.Regexp
+	if config.Expect != "" {
+		re, err := regexp.Compile(config.Expect)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid expect pattern: %w", err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		expect = re
+	}
+
+	header := http.Header{}
+	for k, v := range profile.Headers {
+		header.Set(k, v)
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: timeout,
+		Subprotocols:     config.Subprotocols,
+		Proxy:            http.ProxyFromEnvironment,
+	}
+
+	conn, resp, err := dialer.Dial(profile.GetFullURL(), header)
+	handshake := time.Since(start)
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("handshake: %w", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	result.Timings = append(result.Timings, Timing{Name: "handshake", Duration: handshake})
+	conn.SetReadDeadline(start.Add(timeout))
+	conn.SetWriteDeadline(start.Add(timeout))
+
+	if config.Send != "" || expect != nil {
+		sent := time.Now()
+		if config.Send != "" {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(config.Send)); err != nil {
+				result.Error = fmt.Errorf("send: %w", err)
+				result.Duration = time.Since(start)
+				return result
+			}
+		}
+
+		if err := waitForMessage(conn, expect, &result); err != nil {
+			result.Error = err
+			result.Duration = time.Since(start)
+			return result
+		}
+		if config.Send != "" {
+			result.Timings = append(result.Timings, Timing{Name: "round trip", Duration: time.Since(sent)})
+		}
+	}
+
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second)); err != nil {
+		result.Error = fmt.Errorf("close: %w", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			break
+		}
+	}
+
+	if result.Detail == "" {
+		result.Detail = "connected"
+	}
+	result.Success = true
+	result.Duration = time.Since(start)
+	return result
+}
+
+func waitForMessage(conn *websocket.Conn, expect *regexp.Regexp, result *PingResult) error {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if expect != nil {
+				return fmt.Errorf("waiting for %q: %w", expect.String(), err)
+			}
+			return fmt.Errorf("waiting for reply: %w", err)
+		}
+
+		if expect == nil {
+			result.Detail = firstLine(string(message))
+			return nil
+		}
+		if match := expect.Find(message); match != nil {
+			result.Detail = firstLine(string(match))
+			return nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebSocketServer(t *testing.T) string {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"v1.gateway"}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"welcome"}`))
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(message) == "ping" {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"noise"}`))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"pong"}`))
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWebSocketChecker(t *testing.T) {
+	url := newWebSocketServer(t)
+	headers := map[string]string{"Authorization": "Bearer t0k3n"}
+	ps := NewPingService()
+
+	result := ps.Ping(Profile{Name: "connect", Type: ProfileTypeWebSocket, BaseURL: url, Headers: headers})
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, http.StatusSwitchingProtocols, result.StatusCode)
+	require.Len(t, result.Timings, 1)
+	assert.Equal(t, "handshake", result.Timings[0].Name)
+
+	result = ps.Ping(Profile{Name: "echo", Type: ProfileTypeWebSocket, BaseURL: url, Headers: headers, WebSocket: &WebSocketConfig{
+		Send:         "ping",
+		Expect:       `"type":"pong"`,
+		Subprotocols: []string{"v1.gateway"},
+	}})
+	require.NoError(t, result.Error)
+	assert.True(t, result.Success)
+	assert.Equal(t, `"type":"pong"`, result.Detail)
+	require.Len(t, result.Timings, 2)
+	assert.Equal(t, "round trip", result.Timings[1].Name)
+
+	result = ps.Ping(Profile{Name: "silent", Type: ProfileTypeWebSocket, BaseURL: url, Headers: headers, WebSocket: &WebSocketConfig{
+		Expect:         "never",
+		TimeoutSeconds: 1,
+	}})
+	assert.False(t, result.Success)
+	assert.ErrorContains(t, result.Error, `waiting for "never"`)
+
+	result = ps.Ping(Profile{Name: "unauthorized", Type: ProfileTypeWebSocket, BaseURL: url})
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	assert.ErrorContains(t, result.Error, "handshake")
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	windowDateLayout = "2006-01-02 15:04"
+	windowTimeLayout = "15:04"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+type TimeWindow struct {
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Days     []string `json:"days,omitempty"`
+	TimeZone string   `json:"time_zone,omitempty"`
+}
+
+type resolvedWindow struct {
+	loc        *time.Location
+	recurring  bool
+	start, end time.Time
+	days       map[time.Weekday]bool
+}
+
+func (w TimeWindow) Recurring() bool {
+	return !strings.Contains(w.Start, "-")
+}
+
+func (w TimeWindow) resolve() (resolvedWindow, error) {
+	r := resolvedWindow{loc: time.Local, recurring: w.Recurring()}
+	if w.TimeZone != "" {
+		loc, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return r, err
+		}
+		r.loc = loc
+	}
+
+	layout := windowDateLayout
+	if r.recurring {
+		layout = windowTimeLayout
+	} else if len(w.Days) > 0 {
+		return r, errors.New("days only apply to recurring windows")
+	}
+
+	var err error
+	if r.start, err = time.ParseInLocation(layout, w.Start, r.loc); err != nil {
+		return r, fmt.Errorf("invalid start %q", w.Start)
+	}
+	if r.end, err = time.ParseInLocation(layout, w.End, r.loc); err != nil {
+		return r, fmt.Errorf("invalid end %q", w.End)
+	}
+	if !r.recurring && !r.end.After(r.start) {
+		return r, errors.New("end must be after start")
+	}
+
+	if len(w.Days) > 0 {
+		r.days = make(map[time.Weekday]bool)
+	}
+	for _, day := range w.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return r, fmt.Errorf("invalid day %q", day)
+		}
+		r.days[weekday] = true
+	}
+	return r, nil
+}
+
+func (w TimeWindow) Validate() error {
+	_, err := w.resolve()
+	return err
+}
+
+func (r resolvedWindow) occurrence(day time.Time) (time.Time, time.Time, bool) {
+	if r.days != nil && !r.days[day.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), r.start.Hour(), r.start.Minute(), 0, 0, r.loc)
+	end := time.Date(day.Year(), day.Month(), day.Day(), r.end.Hour(), r.end.Minute(), 0, 0, r.loc)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, true
+}
+
+func (r resolvedWindow) active(now time.Time) bool {
+	now = now.In(r.loc)
+	if !r.recurring {
+		return !now.Before(r.start) && now.Before(r.end)
+	}
+
+	for _, offset := range []int{0, -1} {
+		day := time.Date(now.Year(), now.Month(), now.Day()+offset, 0, 0, 0, 0, r.loc)
+		if start, end, ok := r.occurrence(day); ok && !now.Before(start) && now.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r resolvedWindow) nextStart(after time.Time) (time.Time, bool) {
+	after = after.In(r.loc)
+	if !r.recurring {
+		return r.start, r.start.After(after)
+	}
+
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(after.Year(), after.Month(), after.Day()+offset, 0, 0, 0, 0, r.loc)
+		if start, _, ok := r.occurrence(day); ok && start.After(after) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (w TimeWindow) Active(now time.Time) bool {
+	r, err := w.resolve()
+	return err == nil && r.active(now)
+}
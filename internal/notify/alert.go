@@ -0,0 +1,333 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const (
+	ChannelTerminal = "terminal"
+	ChannelHooks    = "hooks"
+	ChannelEmail    = "email"
+)
+
+var builtinChannels = []string{ChannelTerminal, ChannelHooks, ChannelEmail}
+
+var defaultPolicy = models.EscalationPolicy{
+	Steps: []models.EscalationStep{{AfterFailures: 1}},
+}
+
+type AlertState struct {
+	Profile       string    `json:"profile"`
+	Failures      int       `json:"failures"`
+	DownSince     time.Time `json:"down_since,omitzero"`
+	Step          int       `json:"step"`
+	Channels      []string  `json:"channels,omitempty"`
+	LastNotified  time.Time `json:"last_notified,omitzero"`
+	AckUntil      time.Time `json:"ack_until,omitzero"`
+	SilencedUntil time.Time `json:"silenced_until,omitzero"`
+}
+
+func (s AlertState) Firing() bool {
+	return s.Step > 0
+}
+
+func (s AlertState) Acknowledged(now time.Time) bool {
+	return now.Before(s.AckUntil)
+}
+
+func (s AlertState) Silenced(now time.Time) bool {
+	return now.Before(s.SilencedUntil)
+}
+
+type Dispatch struct {
+	Transition Transition
+	Channels   []string
+}
+
+type Alerter struct {
+	mu       sync.Mutex
+	filePath string
+	policy   models.EscalationPolicy
+	channels map[string]Notifier
+	states   map[string]*AlertState
+	now      func() time.Time
+}
+
+func NewAlerter(filePath string, policy *models.EscalationPolicy) *Alerter {
+	a := &Alerter{
+		filePath: filePath,
+		policy:   defaultPolicy,
+		channels: make(map[string]Notifier),
+		states:   make(map[string]*AlertState),
+		now:      time.Now,
+	}
+	if policy != nil && len(policy.Steps) > 0 {
+		a.policy = *policy
+	}
+	return a
+}
+
+func (a *Alerter) AddChannel(name string, n Notifier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.channels[name] = n
+}
+
+func (a *Alerter) channelNames() []string {
+	names := make([]string, 0, len(a.channels))
+	for name := range a.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (a *Alerter) Load() error {
+	if a.filePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(a.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var states []AlertState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range states {
+		a.states[states[i].Profile] = &states[i]
+	}
+	return nil
+}
+
+func (a *Alerter) save() error {
+	if a.filePath == "" {
+		return nil
+	}
+
+	states := make([]AlertState, 0, len(a.states))
+	for _, state := range a.states {
+		if state.Failures == 0 && state.AckUntil.IsZero() && state.SilencedUntil.IsZero() {
+			continue
+		}
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Profile < states[j].Profile })
+
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return models.WriteFileAtomic(a.filePath, data, 0644)
+}
+
+func (a *Alerter) state(name string) *AlertState {
+	state, ok := a.states[name]
+	if !ok {
+		state = &AlertState{Profile: name}
+		a.states[name] = state
+	}
+	return state
+}
+
+func (a *Alerter) State(name string) AlertState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if state, ok := a.states[name]; ok {
+		return *state
+	}
+	return AlertState{Profile: name}
+}
+
+func (a *Alerter) policyFor(profile models.Profile) models.EscalationPolicy {
+	if profile.Escalation != nil && len(profile.Escalation.Steps) > 0 {
+		return *profile.Escalation
+	}
+	return a.policy
+}
+
+func (a *Alerter) stepReached(step models.EscalationStep, state *AlertState, now time.Time) bool {
+	failures := step.AfterFailures
+	if failures <= 0 {
+		failures = 1
+	}
+	return state.Failures >= failures && now.Sub(state.DownSince) >= time.Duration(step.AfterMinutes)*time.Minute
+}
+
+func (a *Alerter) Evaluate(profile models.Profile, result models.PingResult) ([]Dispatch, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	state := a.state(profile.Name)
+
+	if result.Success {
+		if state.Failures == 0 {
+			return nil, nil
+		}
+
+		channels, failures := state.Channels, state.Failures
+		state.Failures = 0
+		state.DownSince = time.Time{}
+		state.Step = 0
+		state.Channels = nil
+		state.LastNotified = time.Time{}
+		state.AckUntil = time.Time{}
+		if err := a.save(); err != nil {
+			return nil, err
+		}
+
+		if len(channels) == 0 || state.Silenced(now) {
+			return nil, nil
+		}
+		return []Dispatch{{
+			Transition: Transition{Profile: profile, From: StateDown, To: StateUp, Result: result, Failures: failures},
+			Channels:   channels,
+		}}, nil
+	}
+
+	if result.Maintenance != "" {
+		return nil, nil
+	}
+
+	if state.Failures == 0 {
+		state.DownSince = now
+	}
+	state.Failures++
+
+	var channels []string
+	repeat := false
+	if !state.Silenced(now) && !state.Acknowledged(now) {
+		policy := a.policyFor(profile)
+		for state.Step < len(policy.Steps) && a.stepReached(policy.Steps[state.Step], state, now) {
+			stepChannels := policy.Steps[state.Step].Channels
+			if len(stepChannels) == 0 {
+				stepChannels = a.channelNames()
+			}
+			for _, channel := range stepChannels {
+				if !slices.Contains(channels, channel) {
+					channels = append(channels, channel)
+				}
+			}
+			state.Step++
+		}
+
+		if len(channels) == 0 && state.Firing() && policy.RepeatMinutes > 0 &&
+			now.Sub(state.LastNotified) >= time.Duration(policy.RepeatMinutes)*time.Minute {
+			channels = append(channels, state.Channels...)
+			repeat = true
+		}
+	}
+
+	if len(channels) > 0 {
+		state.LastNotified = now
+		for _, channel := range channels {
+			if !slices.Contains(state.Channels, channel) {
+				state.Channels = append(state.Channels, channel)
+			}
+		}
+	}
+	if err := a.save(); err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 {
+		return nil, nil
+	}
+	return []Dispatch{{
+		Transition: Transition{Profile: profile, From: StateUp, To: StateDown, Result: result, Failures: state.Failures, Repeat: repeat},
+		Channels:   channels,
+	}}, nil
+}
+
+func (a *Alerter) Send(dispatches []Dispatch) error {
+	a.mu.Lock()
+	channels := make(map[string]Notifier, len(a.channels))
+	for name, n := range a.channels {
+		channels[name] = n
+	}
+	a.mu.Unlock()
+
+	var errs []error
+	for _, d := range dispatches {
+		for _, name := range d.Channels {
+			n, ok := channels[name]
+			if !ok {
+				if !slices.Contains(builtinChannels, name) {
+					errs = append(errs, fmt.Errorf("unknown alert channel %q", name))
+				}
+				continue
+			}
+			if err := n.Notify(d.Transition); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Alerter) Observe(profile models.Profile, result models.PingResult) error {
+	dispatches, err := a.Evaluate(profile, result)
+	if err != nil {
+		return err
+	}
+	return a.Send(dispatches)
+}
+
+func (a *Alerter) Acknowledge(name string, d time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	state := a.state(name)
+	if !state.Firing() {
+		return fmt.Errorf("no active alert for %q", name)
+	}
+	state.AckUntil = a.now().Add(d)
+	return a.save()
+}
+
+func (a *Alerter) Silence(name string, d time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.state(name).SilencedUntil = a.now().Add(d)
+	return a.save()
+}
+
+func (a *Alerter) Unsilence(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	state := a.state(name)
+	state.AckUntil = time.Time{}
+	state.SilencedUntil = time.Time{}
+	return a.save()
+}
+
+func (a *Alerter) Forget(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.states, name)
+	return a.save()
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	transitions []Transition
+}
+
+func (r *recordingNotifier) Notify(t Transition) error {
+	r.transitions = append(r.transitions, t)
+	return nil
+}
+
+type alertClock struct {
+	now time.Time
+}
+
+func (c *alertClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestAlerter(t *testing.T, path string, policy *models.EscalationPolicy) (*Alerter, *alertClock, *recordingNotifier, *recordingNotifier) {
+	clock := &alertClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	chat, pager := &recordingNotifier{}, &recordingNotifier{}
+
+	a := NewAlerter(path, policy)
+	a.now = func() time.Time { return clock.now }
+	a.AddChannel("chat", chat)
+	a.AddChannel("pager", pager)
+	require.NoError(t, a.Load())
+	return a, clock, chat, pager
+}
+
+var (
+	failing = models.PingResult{StatusCode: 500}
+	healthy = models.PingResult{Success: true, StatusCode: 200}
+)
+
+func TestAlerter_DefaultPolicy(t *testing.T) {
+	a, _, chat, pager := newTestAlerter(t, "", nil)
+	profile := models.Profile{Name: "api"}
+
+	require.NoError(t, a.Observe(profile, healthy))
+	assert.Empty(t, chat.transitions)
+
+	require.NoError(t, a.Observe(profile, failing))
+	require.NoError(t, a.Observe(profile, failing))
+	require.Len(t, chat.transitions, 1)
+	require.Len(t, pager.transitions, 1)
+	assert.Equal(t, StateDown, chat.transitions[0].To)
+
+	require.NoError(t, a.Observe(profile, healthy))
+	require.Len(t, chat.transitions, 2)
+	assert.Equal(t, StateUp, chat.transitions[1].To)
+	assert.Equal(t, 2, chat.transitions[1].Failures)
+	assert.False(t, a.State("api").Firing())
+}
+
+func TestAlerter_Escalation(t *testing.T) {
+	policy := &models.EscalationPolicy{
+		Steps: []models.EscalationStep{
+			{AfterFailures: 2, Channels: []string{"chat"}},
+			{AfterMinutes: 10, Channels: []string{"pager"}},
+		},
+		RepeatMinutes: 30,
+	}
+	a, clock, chat, pager := newTestAlerter(t, "", policy)
+	profile := models.Profile{Name: "api"}
+
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Empty(t, chat.transitions, "first failure is below the threshold")
+
+	clock.advance(time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	require.Len(t, chat.transitions, 1)
+	assert.Empty(t, pager.transitions)
+
+	clock.advance(5 * time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, chat.transitions, 1)
+	assert.Empty(t, pager.transitions)
+
+	clock.advance(5 * time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, chat.transitions, 1)
+	require.Len(t, pager.transitions, 1)
+	assert.Equal(t, 2, a.State("api").Step)
+
+	clock.advance(29 * time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, pager.transitions, 1)
+
+	clock.advance(time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	require.Len(t, chat.transitions, 2)
+	require.Len(t, pager.transitions, 2)
+	assert.True(t, pager.transitions[1].Repeat)
+	assert.Equal(t, "api is still down", pager.transitions[1].Title())
+
+	require.NoError(t, a.Observe(profile, healthy))
+	assert.Len(t, chat.transitions, 3)
+	assert.Len(t, pager.transitions, 3)
+}
+
+func TestAlerter_RecoveryBeforeThresholdIsQuiet(t *testing.T) {
+	policy := &models.EscalationPolicy{Steps: []models.EscalationStep{{AfterFailures: 3}}}
+	a, _, chat, _ := newTestAlerter(t, "", policy)
+	profile := models.Profile{Name: "api"}
+
+	require.NoError(t, a.Observe(profile, failing))
+	require.NoError(t, a.Observe(profile, healthy))
+	assert.Empty(t, chat.transitions)
+}
+
+func TestAlerter_ProfilePolicyOverridesGlobal(t *testing.T) {
+	a, _, chat, pager := newTestAlerter(t, "", nil)
+	profile := models.Profile{
+		Name:       "api",
+		Escalation: &models.EscalationPolicy{Steps: []models.EscalationStep{{Channels: []string{"pager"}}}},
+	}
+
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Empty(t, chat.transitions)
+	assert.Len(t, pager.transitions, 1)
+
+	profile.Escalation.Steps[0].Channels = []string{"sms"}
+	require.NoError(t, a.Forget("api"))
+	assert.ErrorContains(t, a.Observe(profile, failing), `unknown alert channel "sms"`)
+}
+
+func TestAlerter_AcknowledgeAndSilence(t *testing.T) {
+	policy := &models.EscalationPolicy{
+		Steps:         []models.EscalationStep{{Channels: []string{"chat"}}, {AfterFailures: 3, Channels: []string{"pager"}}},
+		RepeatMinutes: 5,
+	}
+	a, clock, chat, pager := newTestAlerter(t, "", policy)
+	profile := models.Profile{Name: "api"}
+
+	assert.ErrorContains(t, a.Acknowledge("api", time.Hour), "no active alert")
+
+	require.NoError(t, a.Observe(profile, failing))
+	require.Len(t, chat.transitions, 1)
+
+	require.NoError(t, a.Acknowledge("api", time.Hour))
+	assert.True(t, a.State("api").Acknowledged(clock.now))
+
+	for range 5 {
+		clock.advance(10 * time.Minute)
+		require.NoError(t, a.Observe(profile, failing))
+	}
+	assert.Len(t, chat.transitions, 1, "acknowledged alerts do not repeat")
+	assert.Empty(t, pager.transitions, "acknowledged alerts do not escalate")
+
+	clock.advance(20 * time.Minute)
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, pager.transitions, 1, "escalation resumes when the acknowledgement expires")
+
+	require.NoError(t, a.Silence("api", time.Hour))
+	require.NoError(t, a.Observe(profile, healthy))
+	assert.Len(t, pager.transitions, 1, "silenced profiles do not send recoveries")
+	assert.True(t, a.State("api").Silenced(clock.now))
+	assert.False(t, a.State("api").Acknowledged(clock.now), "recovery clears the acknowledgement")
+
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, chat.transitions, 1)
+
+	require.NoError(t, a.Unsilence("api"))
+	require.NoError(t, a.Observe(profile, failing))
+	assert.Len(t, chat.transitions, 2)
+}
+
+func TestAlerter_PersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	policy := &models.EscalationPolicy{Steps: []models.EscalationStep{{Channels: []string{"chat"}}}}
+	profile := models.Profile{Name: "api"}
+
+	a, clock, chat, _ := newTestAlerter(t, path, policy)
+	require.NoError(t, a.Observe(profile, failing))
+	require.NoError(t, a.Acknowledge("api", time.Hour))
+	require.Len(t, chat.transitions, 1)
+
+	restarted, _, restartedChat, _ := newTestAlerter(t, path, policy)
+	state := restarted.State("api")
+	assert.Equal(t, 1, state.Failures)
+	assert.True(t, state.Firing())
+	assert.True(t, state.Acknowledged(clock.now))
+
+	require.NoError(t, restarted.Observe(profile, failing))
+	assert.Empty(t, restartedChat.transitions, "restart does not re-send an active alert")
+
+	require.NoError(t, restarted.Observe(profile, healthy))
+	require.Len(t, restartedChat.transitions, 1)
+	assert.Equal(t, StateUp, restartedChat.transitions[0].To)
+}
+
+func TestAlerter_IgnoresMaintenanceFailures(t *testing.T) {
+	a, _, chat, _ := newTestAlerter(t, "", nil)
+	profile := models.Profile{Name: "api"}
+
+	require.NoError(t, a.Observe(profile, models.PingResult{StatusCode: 503, Maintenance: "upgrade"}))
+	assert.Empty(t, chat.transitions)
+	assert.Zero(t, a.State("api").Failures)
+
+	require.NoError(t, a.Observe(profile, failing))
+	require.Len(t, chat.transitions, 1)
+
+	require.NoError(t, a.Observe(profile, models.PingResult{Success: true, Maintenance: "upgrade"}))
+	require.Len(t, chat.transitions, 2, "recoveries during maintenance are still reported")
+}
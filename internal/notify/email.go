@@ -0,0 +1,233 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const (
+	defaultEmailBatch = 5 * time.Second
+	emailTimeout      = 30 * time.Second
+
+	defaultEmailSubject = `[route-keeper] {{if eq (len .Transitions) 1}}{{(index .Transitions 0).Title}}{{else}}{{.Down}} down, {{.Up}} recovered{{end}}`
+	defaultEmailBody    = `{{range .Transitions}}{{.Title}}
+  Target: {{.Profile.Target}}
+  Detail: {{.Summary}}
+  Time:   {{.Result.Timestamp.Format "2006-01-02 15:04:05 MST"}}
+
+{{end}}`
+)
+
+type emailData struct {
+	Transitions []Transition
+	Down        int
+	Up          int
+}
+
+type EmailNotifier struct {
+	settings  models.EmailSettings
+	subject   *template.Template
+	body      *template.Template
+	batch     time.Duration
+	tlsConfig *tls.Config
+	logger    *log.Logger
+
+	mu      sync.Mutex
+	pending []Transition
+	timer   *time.Timer
+}
+
+func NewEmailNotifier(settings models.EmailSettings, logger *log.Logger) (*EmailNotifier, error) {
+	if settings.Host == "" || settings.From == "" || len(settings.To) == 0 {
+		return nil, errors.New("email host, from and to are required")
+	}
+
+	switch settings.TLS {
+	case "", models.EmailTLSStartTLS, models.EmailTLSImplicit, models.EmailTLSNone:
+	default:
+		return nil, fmt.Errorf("unsupported email tls mode %q", settings.TLS)
+	}
+
+	subjectText, bodyText := settings.Subject, settings.Body
+	if subjectText == "" {
+		subjectText = defaultEmailSubject
+	}
+	if bodyText == "" {
+		bodyText = defaultEmailBody
+	}
+
+	subject, err := template.New("subject").Parse(subjectText)
+	if err != nil {
+		return nil, fmt.Errorf("email subject template: %w", err)
+	}
+	body, err := template.New("body").Parse(bodyText)
+	if err != nil {
+		return nil, fmt.Errorf("email body template: %w", err)
+	}
+
+	batch := defaultEmailBatch
+	if settings.BatchSeconds > 0 {
+		batch = time.Duration(settings.BatchSeconds) * time.Second
+	}
+
+	return &EmailNotifier{
+		settings:  settings,
+		subject:   subject,
+		body:      body,
+		batch:     batch,
+		tlsConfig: &tls.Config{ServerName: settings.Host},
+		logger:    logger,
+	}, nil
+}
+
+func (n *EmailNotifier) Notify(t Transition) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.pending = append(n.pending, t)
+	if n.timer == nil {
+		n.timer = time.AfterFunc(n.batch, func() {
+			if err := n.Flush(); err != nil && n.logger != nil {
+				n.logger.Printf("email notification failed: %v", err)
+			}
+		})
+	}
+	return nil
+}
+
+func (n *EmailNotifier) Flush() error {
+	n.mu.Lock()
+	transitions := n.pending
+	n.pending = nil
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	n.mu.Unlock()
+
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	msg, err := n.render(transitions)
+	if err != nil {
+		return err
+	}
+	return n.send(msg)
+}
+
+func (n *EmailNotifier) Close() error {
+	return n.Flush()
+}
+
+func (n *EmailNotifier) render(transitions []Transition) ([]byte, error) {
+	data := emailData{Transitions: transitions}
+	for _, t := range transitions {
+		if t.To == StateDown {
+			data.Down++
+		} else {
+			data.Up++
+		}
+	}
+
+	var subject, body bytes.Buffer
+	if err := n.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("email subject template: %w", err)
+	}
+	if err := n.body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("email body template: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.settings.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.settings.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject.String())))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body.String(), "\r\n", "\n"), "\n", "\r\n"))
+
+	return msg.Bytes(), nil
+}
+
+func (n *EmailNotifier) port() int {
+	if n.settings.Port != 0 {
+		return n.settings.Port
+	}
+	if n.settings.TLS == models.EmailTLSImplicit {
+		return 465
+	}
+	return 587
+}
+
+func (n *EmailNotifier) send(msg []byte) error {
+	addr := net.JoinHostPort(n.settings.Host, strconv.Itoa(n.port()))
+
+	var conn net.Conn
+	var err error
+	if n.settings.TLS == models.EmailTLSImplicit {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: emailTimeout}, "tcp", addr, n.tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, emailTimeout)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	client, err := smtp.NewClient(conn, n.settings.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if n.settings.TLS == "" || n.settings.TLS == models.EmailTLSStartTLS {
+		if err := client.StartTLS(n.tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+
+	if n.settings.Username != "" {
+		auth := smtp.PlainAuth("", n.settings.Username, n.settings.Password, n.settings.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.settings.From); err != nil {
+		return err
+	}
+	for _, to := range n.settings.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
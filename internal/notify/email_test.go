@@ -0,0 +1,248 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMail struct {
+	from string
+	to   []string
+	auth string
+	tls  bool
+	data string
+}
+
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	implicit  bool
+	mails     chan fakeMail
+}
+
+func newFakeSMTPServer(t *testing.T, tlsConfig *tls.Config, implicit bool) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if implicit {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s := &fakeSMTPServer{listener: listener, tlsConfig: tlsConfig, implicit: implicit, mails: make(chan fakeMail, 10)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	mail := fakeMail{tls: s.implicit}
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO":
+			if s.tlsConfig != nil && !mail.tls {
+				reply("250-localhost")
+				reply("250-STARTTLS")
+			} else {
+				reply("250-localhost")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, r, mail.tls = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			decoded, _ := base64.StdEncoding.DecodeString(strings.Fields(line)[2])
+			mail.auth = string(decoded)
+			reply("235 ok")
+		case "MAIL":
+			mail.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			mail.data = data.String()
+			s.mails <- mail
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func testTLSConfig(t *testing.T) (server *tls.Config, client *tls.Config) {
+	srv := httptest.NewUnstartedServer(nil)
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	return &tls.Config{Certificates: srv.TLS.Certificates}, &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+}
+
+func receiveMail(t *testing.T, s *fakeSMTPServer) fakeMail {
+	select {
+	case mail := <-s.mails:
+		return mail
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+		return fakeMail{}
+	}
+}
+
+func TestEmailNotifier_Batches(t *testing.T) {
+	server := newFakeSMTPServer(t, nil, false)
+
+	n, err := NewEmailNotifier(models.EmailSettings{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		TLS:  models.EmailTLSNone,
+		From: "monitor@example.com",
+		To:   []string{"oncall@example.com", "team@example.com"},
+	}, nil)
+	require.NoError(t, err)
+	n.batch = 50 * time.Millisecond
+
+	require.NoError(t, n.Notify(Transition{Profile: models.Profile{Name: "api", BaseURL: "https://api.example.com"}, To: StateDown, Result: models.PingResult{StatusCode: 500}}))
+	require.NoError(t, n.Notify(Transition{Profile: models.Profile{Name: "db", Type: models.ProfileTypeTCP, BaseURL: "db:5432"}, To: StateDown, Result: models.PingResult{Detail: "refused"}}))
+	require.NoError(t, n.Notify(Transition{Profile: models.Profile{Name: "auth"}, From: StateDown, To: StateUp, Result: models.PingResult{Success: true, StatusCode: 200}}))
+
+	mail := receiveMail(t, server)
+	assert.Equal(t, "monitor@example.com", mail.from)
+	assert.Equal(t, []string{"oncall@example.com", "team@example.com"}, mail.to)
+	assert.Contains(t, mail.data, "Subject: [route-keeper] 2 down, 1 recovered\r\n")
+	assert.Contains(t, mail.data, "api is down\r\n  Target: https://api.example.com\r\n  Detail: HTTP 500 in 0s\r\n")
+	assert.Contains(t, mail.data, "db is down\r\n  Target: db:5432\r\n")
+	assert.Contains(t, mail.data, "auth recovered\r\n")
+
+	select {
+	case <-server.mails:
+		t.Fatal("transitions were not batched into one message")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEmailNotifier_StartTLSAndAuth(t *testing.T) {
+	serverTLS, clientTLS := testTLSConfig(t)
+	server := newFakeSMTPServer(t, serverTLS, false)
+
+	n, err := NewEmailNotifier(models.EmailSettings{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "monitor",
+		Password: "hunter2",
+		From:     "monitor@example.com",
+		To:       []string{"oncall@example.com"},
+		Subject:  "{{range .Transitions}}{{.Profile.Name}}={{.Event}} {{end}}",
+		Body:     "custom body",
+	}, nil)
+	require.NoError(t, err)
+	n.tlsConfig = clientTLS
+
+	require.NoError(t, n.Notify(Transition{Profile: models.Profile{Name: "api"}, To: StateDown}))
+	require.NoError(t, n.Close())
+
+	mail := receiveMail(t, server)
+	assert.True(t, mail.tls)
+	assert.Equal(t, "\x00monitor\x00hunter2", mail.auth)
+	assert.Contains(t, mail.data, "Subject: api=down\r\n")
+	assert.True(t, strings.HasSuffix(mail.data, "\r\n\r\ncustom body\r\n"))
+}
+
+func TestEmailNotifier_ImplicitTLS(t *testing.T) {
+	serverTLS, clientTLS := testTLSConfig(t)
+	server := newFakeSMTPServer(t, serverTLS, true)
+
+	n, err := NewEmailNotifier(models.EmailSettings{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		TLS:  models.EmailTLSImplicit,
+		From: "monitor@example.com",
+		To:   []string{"oncall@example.com"},
+	}, nil)
+	require.NoError(t, err)
+	n.tlsConfig = clientTLS
+
+	require.NoError(t, n.Notify(Transition{Profile: models.Profile{Name: "api"}, To: StateDown}))
+	require.NoError(t, n.Flush())
+
+	mail := receiveMail(t, server)
+	assert.True(t, mail.tls)
+	assert.Contains(t, mail.data, "Subject: [route-keeper] api is down\r\n")
+}
+
+func TestNewEmailNotifier_Validation(t *testing.T) {
+	_, err := NewEmailNotifier(models.EmailSettings{Host: "smtp.example.com"}, nil)
+	assert.ErrorContains(t, err, "required")
+
+	valid := models.EmailSettings{Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}}
+
+	invalidTLS := valid
+	invalidTLS.TLS = "ssl"
+	_, err = NewEmailNotifier(invalidTLS, nil)
+	assert.ErrorContains(t, err, "unsupported email tls mode")
+
+	invalidTemplate := valid
+	invalidTemplate.Subject = "{{.Missing"
+	_, err = NewEmailNotifier(invalidTemplate, nil)
+	assert.ErrorContains(t, err, "subject template")
+
+	n, err := NewEmailNotifier(valid, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 587, n.port())
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const defaultHookTimeout = 30 * time.Second
+
+type hookPayload struct {
+	Event      string    `json:"event"`
+	Profile    string    `json:"profile"`
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+func newHookPayload(t Transition) hookPayload {
+	payload := hookPayload{
+		Event:      t.Event(),
+		Profile:    t.Profile.Name,
+		URL:        t.Profile.Target(),
+		StatusCode: t.Result.StatusCode,
+		DurationMS: t.Result.Duration.Milliseconds(),
+		Timestamp:  t.Result.Timestamp,
+	}
+	if !t.Result.Success {
+		payload.Error = t.Summary()
+	}
+	return payload
+}
+
+func (p hookPayload) env() []string {
+	return []string{
+		"ROUTE_KEEPER_EVENT=" + p.Event,
+		"ROUTE_KEEPER_PROFILE=" + p.Profile,
+		"ROUTE_KEEPER_URL=" + p.URL,
+		"ROUTE_KEEPER_STATUS_CODE=" + strconv.Itoa(p.StatusCode),
+		"ROUTE_KEEPER_ERROR=" + p.Error,
+		"ROUTE_KEEPER_DURATION_MS=" + strconv.FormatInt(p.DurationMS, 10),
+	}
+}
+
+type HookRunner struct {
+	global []models.HookConfig
+	logger *log.Logger
+}
+
+func NewHookRunner(global []models.HookConfig, logger *log.Logger) *HookRunner {
+	return &HookRunner{
+		global: global,
+		logger: logger,
+	}
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func (r *HookRunner) Notify(t Transition) error {
+	hooks := append(append([]models.HookConfig(nil), r.global...), t.Profile.Hooks...)
+
+	var errs []error
+	for _, hook := range hooks {
+		if hook.Command == "" || !hook.Matches(t.Event()) {
+			continue
+		}
+		if err := r.run(hook, t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *HookRunner) run(hook models.HookConfig, t Transition) error {
+	timeout := defaultHookTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	payload := newHookPayload(t)
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	cmd := shellCommand(ctx, hook.Command)
+	cmd.Env = append(os.Environ(), payload.env()...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+
+	if r.logger != nil {
+		status := "ok"
+		if err != nil {
+			status = err.Error()
+		}
+		r.logger.Printf("hook %q for %s (%s): %s", hook.Command, t.Profile.Name, payload.Event, status)
+		if out := strings.TrimSpace(string(output)); out != "" {
+			r.logger.Printf("hook %q output:\n%s", hook.Command, out)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("hook %q: %w", hook.Command, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookRunner_Notify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	stdinFile := filepath.Join(dir, "stdin")
+
+	var logs bytes.Buffer
+	runner := NewHookRunner([]models.HookConfig{
+		{Command: `echo "$ROUTE_KEEPER_EVENT $ROUTE_KEEPER_PROFILE $ROUTE_KEEPER_STATUS_CODE $ROUTE_KEEPER_ERROR" > ` + envFile + `; cat > ` + stdinFile + `; echo paged`},
+		{Command: "touch " + filepath.Join(dir, "recovered"), On: models.HookOnUp},
+	}, log.New(&logs, "", 0))
+
+	transition := Transition{
+		Profile: models.Profile{Name: "api", BaseURL: "https://api.example.com", Route: "/health"},
+		From:    StateUp,
+		To:      StateDown,
+		Result:  models.PingResult{StatusCode: 503, Duration: 120 * time.Millisecond},
+	}
+	require.NoError(t, runner.Notify(transition))
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, "down api 503 HTTP 503 in 120ms\n", string(env))
+
+	var payload hookPayload
+	stdin, err := os.ReadFile(stdinFile)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(stdin, &payload))
+	assert.Equal(t, "down", payload.Event)
+	assert.Equal(t, "https://api.example.com/health", payload.URL)
+	assert.Equal(t, int64(120), payload.DurationMS)
+
+	assert.NoFileExists(t, filepath.Join(dir, "recovered"))
+	assert.Contains(t, logs.String(), "paged")
+
+	transition.Profile.Hooks = []models.HookConfig{{Command: "exit 3", On: models.HookOnUp}}
+	transition.From, transition.To = StateDown, StateUp
+	transition.Result = models.PingResult{Success: true, StatusCode: 200}
+	err = runner.Notify(transition)
+	assert.ErrorContains(t, err, "exit status 3")
+	assert.FileExists(t, filepath.Join(dir, "recovered"))
+}
+
+func TestHookRunner_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	runner := NewHookRunner([]models.HookConfig{{Command: "sleep 5", TimeoutSeconds: 1}}, nil)
+
+	start := time.Now()
+	err := runner.Notify(Transition{Profile: models.Profile{Name: "api"}, To: StateDown, Result: models.PingResult{Error: errors.New("boom")}})
+	assert.ErrorContains(t, err, "timed out")
+	assert.Less(t, time.Since(start), 4*time.Second)
+}
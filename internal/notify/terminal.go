@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+const execTimeout = 10 * time.Second
+
+type TerminalNotifier struct {
+	settings models.NotificationSettings
+	out      io.Writer
+}
+
+func NewTerminalNotifier(settings models.NotificationSettings, out io.Writer) *TerminalNotifier {
+	return &TerminalNotifier{
+		settings: settings,
+		out:      out,
+	}
+}
+
+func sanitizeOSC(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+func (n *TerminalNotifier) Sequence(t Transition) string {
+	var b strings.Builder
+
+	if n.settings.Bell {
+		b.WriteString("\a")
+	}
+
+	title := sanitizeOSC("route-keeper: " + t.Title())
+	body := sanitizeOSC(t.Summary())
+	switch n.settings.OSC {
+	case "9":
+		b.WriteString("\x1b]9;" + title + ": " + body + "\x07")
+	case "777":
+		b.WriteString("\x1b]777;notify;" + title + ";" + body + "\x07")
+	}
+
+	return b.String()
+}
+
+func (n *TerminalNotifier) Notify(t Transition) error {
+	if seq := n.Sequence(t); seq != "" && n.out != nil {
+		if _, err := io.WriteString(n.out, seq); err != nil {
+			return err
+		}
+	}
+
+	if n.settings.Exec == "" {
+		return nil
+	}
+
+	fields := strings.Fields(n.settings.Exec)
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	args := append(fields[1:], "route-keeper: "+t.Title(), t.Summary())
+	return exec.CommandContext(ctx, fields[0], args...).Run()
+}
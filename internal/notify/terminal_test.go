@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalNotifier_Sequence(t *testing.T) {
+	transition := Transition{
+		Profile: models.Profile{Name: "api;prod"},
+		To:      StateDown,
+		Result:  models.PingResult{StatusCode: 503},
+	}
+
+	tests := []struct {
+		name     string
+		settings models.NotificationSettings
+		want     string
+	}{
+		{"disabled", models.NotificationSettings{}, ""},
+		{"bell", models.NotificationSettings{Bell: true}, "\a"},
+		{"osc9", models.NotificationSettings{OSC: "9"}, "\x1b]9;route-keeper: api prod is down: HTTP 503 in 0s\x07"},
+		{"osc777", models.NotificationSettings{Bell: true, OSC: "777"}, "\a\x1b]777;notify;route-keeper: api prod is down;HTTP 503 in 0s\x07"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			n := NewTerminalNotifier(tt.settings, &out)
+			assert.Equal(t, tt.want, n.Sequence(transition))
+
+			require.NoError(t, n.Notify(transition))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestTerminalNotifier_Exec(t *testing.T) {
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "notify-send")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > "+outFile+"\n"), 0755))
+
+	n := NewTerminalNotifier(models.NotificationSettings{Exec: script + " --urgency=critical"}, nil)
+	err := n.Notify(Transition{
+		Profile: models.Profile{Name: "api"},
+		From:    StateDown,
+		To:      StateUp,
+		Result:  models.PingResult{Success: true, Detail: "connected"},
+	})
+	require.NoError(t, err)
+
+	args, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Equal(t, "--urgency=critical\nroute-keeper: api recovered\nconnected\n", string(args))
+}
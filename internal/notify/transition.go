@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+type State int
+
+const (
+	StateUnknown State = iota
+	StateUp
+	StateDown
+)
+
+func (s State) String() string {
+	switch s {
+	case StateUp:
+		return "UP"
+	case StateDown:
+		return "DOWN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type Notifier interface {
+	Notify(t Transition) error
+}
+
+type Transition struct {
+	Profile  models.Profile
+	From     State
+	To       State
+	Result   models.PingResult
+	Failures int
+	Repeat   bool
+}
+
+func (t Transition) Event() string {
+	if t.To == StateDown {
+		return models.HookOnDown
+	}
+	return models.HookOnUp
+}
+
+func (t Transition) Title() string {
+	if t.To == StateDown && t.Repeat {
+		return fmt.Sprintf("%s is still down", t.Profile.Name)
+	}
+	if t.To == StateDown {
+		return fmt.Sprintf("%s is down", t.Profile.Name)
+	}
+	return fmt.Sprintf("%s recovered", t.Profile.Name)
+}
+
+func (t Transition) Summary() string {
+	result := t.Result
+	switch {
+	case result.AuthError != nil:
+		return "auth error: " + result.AuthError.Error()
+	case result.Error != nil:
+		return result.Error.Error()
+	case result.StatusCode > 0:
+		return fmt.Sprintf("HTTP %d in %v", result.StatusCode, result.Duration.Round(1e6))
+	case result.Detail != "":
+		return result.Detail
+	default:
+		return t.To.String()
+	}
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransition_TitleAndSummary(t *testing.T) {
+	profile := models.Profile{Name: "api"}
+
+	tests := []struct {
+		name       string
+		transition Transition
+		title      string
+		summary    string
+		event      string
+	}{
+		{
+			name:       "down with status",
+			transition: Transition{Profile: profile, To: StateDown, Result: models.PingResult{StatusCode: 500, Duration: 1500 * time.Microsecond}},
+			title:      "api is down",
+			summary:    "HTTP 500 in 2ms",
+			event:      "down",
+		},
+		{
+			name:       "repeat with error",
+			transition: Transition{Profile: profile, To: StateDown, Repeat: true, Result: models.PingResult{Error: errors.New("connection refused")}},
+			title:      "api is still down",
+			summary:    "connection refused",
+			event:      "down",
+		},
+		{
+			name:       "auth error",
+			transition: Transition{Profile: profile, To: StateDown, Result: models.PingResult{AuthError: errors.New("invalid_client")}},
+			title:      "api is down",
+			summary:    "auth error: invalid_client",
+			event:      "down",
+		},
+		{
+			name:       "recovered with detail",
+			transition: Transition{Profile: profile, From: StateDown, To: StateUp, Result: models.PingResult{Success: true, Detail: "connected"}},
+			title:      "api recovered",
+			summary:    "connected",
+			event:      "up",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.title, tt.transition.Title())
+			assert.Equal(t, tt.summary, tt.transition.Summary())
+			assert.Equal(t, tt.event, tt.transition.Event())
+		})
+	}
+}
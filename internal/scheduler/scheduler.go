@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+type CheckFunc func(profile models.Profile)
+
+type Scheduler struct {
+	mu      sync.Mutex
+	check   CheckFunc
+	logger  *log.Logger
+	cancels map[string]context.CancelFunc
+	wg      sync.WaitGroup
+
+	now    func() time.Time
+	jitter func(max time.Duration) time.Duration
+}
+
+func New(check CheckFunc, logger *log.Logger) *Scheduler {
+	return &Scheduler{
+		check:   check,
+		logger:  logger,
+		cancels: make(map[string]context.CancelFunc),
+		now:     time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return rand.N(max)
+		},
+	}
+}
+
+func (s *Scheduler) firstRun(profile models.Profile) (time.Time, error) {
+	now := s.now()
+	if profile.Schedule == nil || profile.Schedule.Cron == "" {
+		if err := profile.Schedule.Validate(); err != nil {
+			return time.Time{}, err
+		}
+		if profile.ActiveAt(now) {
+			return now, nil
+		}
+	}
+	return profile.NextRun(now)
+}
+
+func (s *Scheduler) Schedule(profile models.Profile) error {
+	next, err := s.firstRun(profile)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[profile.Name]; ok {
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancels[profile.Name] = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			if delay := next.Sub(s.now()) + s.jitter(profile.Schedule.Jitter()); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+
+			s.check(profile)
+
+			var err error
+			if next, err = profile.NextRun(next); err != nil {
+				s.logger.Printf("Stopped scheduling %s: %v", profile.Name, err)
+				return
+			}
+			if now := s.now(); next.Before(now) {
+				if next, err = profile.NextRun(now); err != nil {
+					s.logger.Printf("Stopped scheduling %s: %v", profile.Name, err)
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *Scheduler) Unschedule(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[name]; ok {
+		cancel()
+		delete(s.cancels, name)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	for name, cancel := range s.cancels {
+		cancel()
+		delete(s.cancels, name)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
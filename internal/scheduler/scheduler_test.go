@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunsImmediatelyAndReschedules(t *testing.T) {
+	var mu sync.Mutex
+	var checked []string
+
+	s := New(func(profile models.Profile) {
+		mu.Lock()
+		checked = append(checked, profile.Name+":"+profile.Route)
+		mu.Unlock()
+	}, log.New(io.Discard, "", 0))
+
+	require.NoError(t, s.Schedule(models.Profile{Name: "api", Route: "/v1", Interval: 60}))
+	require.NoError(t, s.Schedule(models.Profile{Name: "api", Route: "/v2", Interval: 60}))
+	s.Unschedule("missing")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(checked) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	s.Stop()
+	assert.Empty(t, s.cancels)
+	assert.ElementsMatch(t, []string{"api:/v1", "api:/v2"}, checked)
+}
+
+func TestScheduler_FirstRun(t *testing.T) {
+	s := New(func(models.Profile) {}, log.New(io.Discard, "", 0))
+	now := time.Date(2025, 6, 11, 20, 10, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	first, err := s.firstRun(models.Profile{Name: "interval", Interval: 5})
+	require.NoError(t, err)
+	assert.Equal(t, now, first)
+
+	first, err = s.firstRun(models.Profile{Name: "cron", Schedule: &models.ScheduleConfig{Cron: "*/15 * * * *", TimeZone: "UTC"}})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 11, 20, 15, 0, 0, time.UTC), first)
+
+	businessHours := &models.ScheduleConfig{
+		TimeZone:    "UTC",
+		ActiveHours: []models.TimeWindow{{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}},
+	}
+	first, err = s.firstRun(models.Profile{Name: "office", Interval: 5, Schedule: businessHours})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC), first)
+
+	err = s.Schedule(models.Profile{Name: "broken", Schedule: &models.ScheduleConfig{Cron: "every minute"}})
+	assert.ErrorContains(t, err, "must have 5 fields")
+	assert.Empty(t, s.cancels)
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	s := New(func(models.Profile) {}, log.New(io.Discard, "", 0))
+
+	for range 100 {
+		d := s.jitter(10 * time.Second)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, 10*time.Second)
+	}
+	assert.Zero(t, s.jitter(0))
+}
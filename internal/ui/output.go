@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"os"
+	"sync"
+)
+
+type TerminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func NewTerminalOutput(file *os.File) *TerminalOutput {
+	return &TerminalOutput{File: file}
+}
+
+func (o *TerminalOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.File.Write(p)
+}
+
+func (o *TerminalOutput) WriteString(s string) (int, error) {
+	return o.Write([]byte(s))
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalOutput_SerializesWrites(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	output := NewTerminalOutput(file)
+	frame := strings.Repeat("x", 64*1024)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			io.WriteString(output, frame)
+		}()
+		go func() {
+			defer wg.Done()
+			io.WriteString(output, "\a")
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+	for _, chunk := range strings.Split(string(data), "\a") {
+		assert.Zero(t, len(chunk)%len(frame), "a bell landed inside a frame")
+	}
+}
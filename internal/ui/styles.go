@@ -63,6 +63,10 @@ var (
 			Foreground(errorColor).
 			Bold(true)
 
+	changedStyle = lipgloss.NewStyle().
+			Foreground(secondaryColor).
+			Bold(true)
+
 	inputStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder(), false, false, false, false).
 			BorderBottom(true).
@@ -108,4 +112,9 @@ var (
 	statusInactiveStyle = lipgloss.NewStyle().
 				Foreground(dimTextColor).
 				Faint(true)
+
+	toastStyle = lipgloss.NewStyle().
+			Foreground(accentColor).
+			Bold(true).
+			Padding(0, 4)
 )
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lutefd/route-keeper/internal/models"
+)
+
+type treeRowKind int
+
+const (
+	treeProfileRow treeRowKind = iota
+	treeGroupRow
+	treeTagRow
+)
+
+type treeRow struct {
+	kind     treeRowKind
+	key      string
+	label    string
+	depth    int
+	count    int
+	profile  models.Profile
+	selector models.ProfileSelector
+}
+
+type groupNode struct {
+	name     string
+	path     string
+	children map[string]*groupNode
+	profiles []models.Profile
+}
+
+func newGroupNode(name, path string) *groupNode {
+	return &groupNode{name: name, path: path, children: make(map[string]*groupNode)}
+}
+
+func (n *groupNode) size() int {
+	size := len(n.profiles)
+	for _, child := range n.children {
+		size += child.size()
+	}
+	return size
+}
+
+func buildGroupTree(profiles []models.Profile) *groupNode {
+	root := newGroupNode("", "")
+	for _, profile := range profiles {
+		node := root
+		for _, folder := range models.GroupPath(profile.Group) {
+			child, ok := node.children[folder]
+			if !ok {
+				path := folder
+				if node.path != "" {
+					path = node.path + models.GroupSeparator + folder
+				}
+				child = newGroupNode(folder, path)
+				node.children[folder] = child
+			}
+			node = child
+		}
+		node.profiles = append(node.profiles, profile)
+	}
+	return root
+}
+
+func groupKey(path string) string {
+	return "group:" + path
+}
+
+func tagKey(tag string) string {
+	return "tag:" + tag
+}
+
+func (m *MainModel) isOpen(key string) bool {
+	if open, ok := m.treeOpen[key]; ok {
+		return open
+	}
+	return strings.HasPrefix(key, "group:")
+}
+
+func (m *MainModel) setOpen(key string, open bool) {
+	if m.treeOpen == nil {
+		m.treeOpen = make(map[string]bool)
+	}
+	m.treeOpen[key] = open
+}
+
+func (m *MainModel) profileRows() []treeRow {
+	profiles := m.ProfilesManager.GetProfiles()
+
+	var rows []treeRow
+	var walk func(node *groupNode, depth int)
+	walk = func(node *groupNode, depth int) {
+		names := make([]string, 0, len(node.children))
+		for name := range node.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := node.children[name]
+			row := treeRow{
+				kind:     treeGroupRow,
+				key:      groupKey(child.path),
+				label:    child.name,
+				depth:    depth,
+				count:    child.size(),
+				selector: models.ProfileSelector{Group: child.path},
+			}
+			rows = append(rows, row)
+			if m.isOpen(row.key) {
+				walk(child, depth+1)
+			}
+		}
+		for _, profile := range node.profiles {
+			rows = append(rows, treeRow{kind: treeProfileRow, label: profile.Name, depth: depth, profile: profile})
+		}
+	}
+	walk(buildGroupTree(profiles), 0)
+
+	for _, tag := range models.ProfileTags(profiles) {
+		selector := models.ProfileSelector{Tag: tag}
+		tagged := models.SelectProfiles(profiles, selector)
+		row := treeRow{
+			kind:     treeTagRow,
+			key:      tagKey(tag),
+			label:    "#" + tag,
+			count:    len(tagged),
+			selector: selector,
+		}
+		rows = append(rows, row)
+		if !m.isOpen(row.key) {
+			continue
+		}
+		for _, profile := range tagged {
+			rows = append(rows, treeRow{kind: treeProfileRow, label: profile.Name, depth: 1, profile: profile})
+		}
+	}
+	return rows
+}
+
+func (m *MainModel) selectedRow() (treeRow, bool) {
+	rows := m.profileRows()
+	if m.ProfileIndex < 0 || m.ProfileIndex >= len(rows) {
+		return treeRow{}, false
+	}
+	return rows[m.ProfileIndex], true
+}
+
+func (m *MainModel) clampProfileIndex() {
+	rows := len(m.profileRows())
+	if m.ProfileIndex >= rows {
+		m.ProfileIndex = rows - 1
+	}
+	if m.ProfileIndex < 0 {
+		m.ProfileIndex = 0
+	}
+}
@@ -1,14 +1,22 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/route-keeper/internal/daemon"
 	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
+	"github.com/lutefd/route-keeper/internal/scheduler"
 )
 
 type ViewState int
@@ -19,15 +27,73 @@ const (
 	CreateProfileView
 	EditProfileView
 	RunningView
+	DiffView
+	GroupRunningView
+)
+
+const (
+	attachPollInterval = 5 * time.Second
+	alertSnooze        = time.Hour
 )
 
 type tickMsg time.Time
 type pingResultMsg models.PingResult
 
+type scheduledPingMsg struct {
+	profile string
+	result  models.PingResult
+}
+
+type schedulerErrorMsg string
+
+type historyMsg struct {
+	records []models.PingRecord
+	err     error
+}
+
+type groupResultsMsg struct {
+	results map[string]models.PingResult
+	err     error
+}
+
+type notifyFailedMsg struct {
+	err error
+}
+
+type profilesRefreshedMsg struct {
+	err error
+}
+
+type profilesReloadedMsg struct {
+	before  []models.Profile
+	changed bool
+	err     error
+}
+
+type clearToastMsg int
+
+type profileReloader interface {
+	ReloadIfChanged() (bool, error)
+}
+
+const (
+	profileReloadInterval = 2 * time.Second
+	toastDuration         = 4 * time.Second
+)
+
+type ProfileStore interface {
+	GetProfiles() []models.Profile
+	AddProfile(profile models.Profile) error
+	UpdateProfile(name string, profile models.Profile) error
+	DeleteProfile(name string) error
+}
+
 type MainModel struct {
 	State           ViewState
-	ProfilesManager *models.ProfilesManager
+	ProfilesManager ProfileStore
 	PingService     *models.PingService
+	Daemon          *daemon.Client
+	Alerts          *notify.Alerter
 
 	MenuIndex    int
 	ProfileIndex int
@@ -36,18 +102,35 @@ type MainModel struct {
 	InputIndex     int
 	Inputs         []textinput.Model
 	IsEditing      bool
+	FormErrors     map[string]string
 
 	CurrentProfile models.Profile
 	IsRunning      bool
-	Ticker         *time.Ticker
+	RunSelector    models.ProfileSelector
+	GroupProfiles  []models.Profile
+	GroupResults   map[string]models.PingResult
+	Scheduler      *scheduler.Scheduler
 	PingResults    []models.PingResult
+	Notice         string
+	Toast          string
+
+	DiffResult models.PingResult
+	DiffOffset int
 
 	Width  int
 	Height int
+
+	results   *resultQueue
+	listening bool
+
+	toastSeq  int
+	reloadErr string
+	treeOpen  map[string]bool
+	observed  map[string]time.Time
 }
 
-func NewMainModel(pm *models.ProfilesManager) *MainModel {
-	inputs := make([]textinput.Model, 6)
+func NewMainModel(pm ProfileStore) *MainModel {
+	inputs := make([]textinput.Model, 8)
 
 	inputs[0] = textinput.New()
 	inputs[0].Placeholder = "Profile name"
@@ -68,17 +151,109 @@ func NewMainModel(pm *models.ProfilesManager) *MainModel {
 	inputs[5] = textinput.New()
 	inputs[5].Placeholder = "5"
 
+	inputs[6] = textinput.New()
+	inputs[6].Placeholder = "payments/eu"
+
+	inputs[7] = textinput.New()
+	inputs[7].Placeholder = "prod,critical"
+
+	ps := models.NewPingService()
+	results := newResultQueue()
+
 	return &MainModel{
 		State:           MainMenuView,
 		ProfilesManager: pm,
-		PingService:     models.NewPingService(),
-		Inputs:          inputs,
-		PingResults:     []models.PingResult{},
+		PingService:     ps,
+		Scheduler: scheduler.New(func(profile models.Profile) {
+			results.push(scheduledPingMsg{profile: profile.Name, result: ps.Ping(profile)})
+		}, log.New(results, "", 0)),
+		Alerts:      notify.NewAlerter("", nil),
+		Inputs:      inputs,
+		PingResults: []models.PingResult{},
+		results:     results,
+		observed:    make(map[string]time.Time),
 	}
 }
 
+func NewAttachedModel(client *daemon.Client) *MainModel {
+	m := NewMainModel(client)
+	m.Daemon = client
+	return m
+}
+
 func (m *MainModel) Init() tea.Cmd {
-	return textinput.Blink
+	if m.Daemon != nil {
+		return tea.Batch(textinput.Blink, m.refreshProfiles())
+	}
+	return tea.Batch(textinput.Blink, m.watchProfiles())
+}
+
+func (m *MainModel) watchProfiles() tea.Cmd {
+	store, ok := m.ProfilesManager.(profileReloader)
+	if !ok {
+		return nil
+	}
+	return tea.Tick(profileReloadInterval, func(time.Time) tea.Msg {
+		before := m.ProfilesManager.GetProfiles()
+		changed, err := store.ReloadIfChanged()
+		return profilesReloadedMsg{before: before, changed: changed, err: err}
+	})
+}
+
+func (m *MainModel) showToast(text string) tea.Cmd {
+	m.Toast = text
+	m.toastSeq++
+	seq := m.toastSeq
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return clearToastMsg(seq)
+	})
+}
+
+func (m *MainModel) applyReload(msg profilesReloadedMsg) tea.Cmd {
+	cmds := []tea.Cmd{m.watchProfiles()}
+
+	errText := ""
+	if msg.err != nil {
+		errText = strings.SplitN(msg.err.Error(), "\n", 2)[0]
+	}
+	reportErr := errText != "" && errText != m.reloadErr
+	m.reloadErr = errText
+
+	if !msg.changed {
+		if reportErr {
+			cmds = append(cmds, m.showToast("⚠ Reload failed: "+errText))
+		}
+		return tea.Batch(cmds...)
+	}
+
+	profiles := m.ProfilesManager.GetProfiles()
+	m.clampProfileIndex()
+
+	toast := "↻ Profiles reloaded"
+	if m.IsRunning && m.Daemon == nil && m.State == GroupRunningView {
+		toast += m.reloadGroup(msg.before, profiles)
+	} else if m.IsRunning && m.Daemon == nil {
+		changed, removed := models.DiffProfiles(msg.before, profiles)
+		if slices.Contains(removed, m.CurrentProfile.Name) {
+			toast = fmt.Sprintf("↻ Profiles reloaded; %s was removed", m.CurrentProfile.Name)
+			m.stopRunning()
+		}
+		for _, profile := range changed {
+			if profile.Name != m.CurrentProfile.Name {
+				continue
+			}
+			m.CurrentProfile = profile
+			if err := m.Scheduler.Schedule(profile); err != nil {
+				m.Notice = "Could not schedule checks: " + err.Error()
+			}
+		}
+	}
+	if errText != "" {
+		toast += " with errors: " + errText
+	}
+
+	cmds = append(cmds, m.showToast(toast))
+	return tea.Batch(cmds...)
 }
 
 func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -91,18 +266,87 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKeyPress(msg)
 
 	case tickMsg:
-		if m.IsRunning {
+		if m.IsRunning && m.Daemon != nil && m.State == GroupRunningView {
+			return m, tea.Batch(
+				m.fetchGroupResults(),
+				m.tick(),
+			)
+		}
+		if m.IsRunning && m.Daemon != nil {
 			return m, tea.Batch(
-				m.doPing(),
+				m.fetchHistory(),
 				m.tick(),
 			)
 		}
 
+	case scheduledPingMsg:
+		wait := m.waitForResult()
+		if profile, ok := m.groupProfile(msg.profile); ok && m.IsRunning {
+			m.GroupResults[profile.Name] = msg.result
+			return m, tea.Batch(wait, m.observe(profile, msg.result))
+		}
+		if m.IsRunning && m.State != GroupRunningView && msg.profile == m.CurrentProfile.Name {
+			_, cmd := m.Update(pingResultMsg(msg.result))
+			return m, tea.Batch(wait, cmd)
+		}
+		return m, wait
+
+	case schedulerErrorMsg:
+		m.Notice = string(msg)
+		return m, m.waitForResult()
+
 	case pingResultMsg:
 		m.PingResults = append([]models.PingResult{models.PingResult(msg)}, m.PingResults...)
 		if len(m.PingResults) > 20 {
 			m.PingResults = m.PingResults[:20]
 		}
+		return m, m.observe(m.CurrentProfile, models.PingResult(msg))
+
+	case historyMsg:
+		if msg.err != nil {
+			m.Notice = "Daemon unavailable: " + msg.err.Error()
+			break
+		}
+		m.Notice = ""
+		m.PingResults = make([]models.PingResult, 0, len(msg.records))
+		for _, record := range msg.records {
+			m.PingResults = append(m.PingResults, record.Result())
+		}
+		if len(m.PingResults) > 0 {
+			return m, m.observeRecord(m.CurrentProfile, m.PingResults[0])
+		}
+
+	case groupResultsMsg:
+		if msg.err != nil {
+			m.Notice = "Daemon unavailable: " + msg.err.Error()
+			break
+		}
+		m.Notice = ""
+		var cmds []tea.Cmd
+		for _, profile := range m.GroupProfiles {
+			if result, ok := msg.results[profile.Name]; ok {
+				m.GroupResults[profile.Name] = result
+				cmds = append(cmds, m.observeRecord(profile, result))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case notifyFailedMsg:
+		m.Notice = "Alert failed: " + msg.err.Error()
+
+	case profilesReloadedMsg:
+		return m, m.applyReload(msg)
+
+	case clearToastMsg:
+		if int(msg) == m.toastSeq {
+			m.Toast = ""
+		}
+
+	case profilesRefreshedMsg:
+		m.Notice = ""
+		if msg.err != nil {
+			m.Notice = "Daemon unavailable: " + msg.err.Error()
+		}
 	}
 
 	if m.State == CreateProfileView || m.State == EditProfileView {
@@ -116,9 +360,13 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *MainModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
-		if m.State == RunningView {
+		if m.State == RunningView || m.State == GroupRunningView {
 			return m.stopRunning(), nil
 		}
+		if m.State == DiffView {
+			m.State = RunningView
+			return m, nil
+		}
 		return m, tea.Quit
 
 	case "esc":
@@ -126,8 +374,11 @@ func (m *MainModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case ProfileListView, CreateProfileView, EditProfileView:
 			m.State = MainMenuView
 			m.MenuIndex = 0
-		case RunningView:
+		case RunningView, GroupRunningView:
 			return m.stopRunning(), nil
+		case DiffView:
+			m.State = RunningView
+			return m, nil
 		}
 	}
 
@@ -140,6 +391,10 @@ func (m *MainModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleProfileFormKeys(msg)
 	case RunningView:
 		return m.handleRunningKeys(msg)
+	case GroupRunningView:
+		return m.handleGroupRunningKeys(msg)
+	case DiffView:
+		return m.handleDiffKeys(msg)
 	}
 
 	return m, nil
@@ -160,6 +415,9 @@ func (m *MainModel) handleMainMenuKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case 0:
 			m.State = ProfileListView
 			m.ProfileIndex = 0
+			if m.Daemon != nil {
+				return m, m.refreshProfiles()
+			}
 		case 1:
 			m.State = CreateProfileView
 			m.IsEditing = false
@@ -172,7 +430,8 @@ func (m *MainModel) handleMainMenuKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *MainModel) handleProfileListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	profiles := m.ProfilesManager.GetProfiles()
+	rows := m.profileRows()
+	row, ok := m.selectedRow()
 
 	switch msg.String() {
 	case "up", "k":
@@ -180,32 +439,44 @@ func (m *MainModel) handleProfileListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.ProfileIndex--
 		}
 	case "down", "j":
-		if m.ProfileIndex < len(profiles)-1 {
+		if m.ProfileIndex < len(rows)-1 {
 			m.ProfileIndex++
 		}
 	case "enter":
-		if len(profiles) > 0 {
-			m.CurrentProfile = profiles[m.ProfileIndex]
-			m.State = RunningView
-			return m.startRunning()
+		if !ok {
+			break
+		}
+		if row.kind != treeProfileRow {
+			m.setOpen(row.key, !m.isOpen(row.key))
+			break
+		}
+		m.CurrentProfile = row.profile
+		m.State = RunningView
+		return m.startRunning()
+	case "right", "l":
+		if ok && row.kind != treeProfileRow {
+			m.setOpen(row.key, true)
+		}
+	case "left", "h":
+		if ok && row.kind != treeProfileRow {
+			m.setOpen(row.key, false)
+			m.clampProfileIndex()
+		}
+	case "g":
+		if ok && row.kind != treeProfileRow {
+			return m.startGroup(row.selector)
 		}
 	case "e":
-		if len(profiles) > 0 {
-			m.EditingProfile = profiles[m.ProfileIndex]
+		if ok && row.kind == treeProfileRow {
+			m.EditingProfile = row.profile
 			m.populateInputsFromProfile(m.EditingProfile)
 			m.State = EditProfileView
 			m.IsEditing = true
 		}
 	case "d":
-		if len(profiles) > 0 {
-			profile := profiles[m.ProfileIndex]
-			m.ProfilesManager.DeleteProfile(profile.Name)
-			if m.ProfileIndex >= len(m.ProfilesManager.GetProfiles()) {
-				m.ProfileIndex = len(m.ProfilesManager.GetProfiles()) - 1
-			}
-			if m.ProfileIndex < 0 {
-				m.ProfileIndex = 0
-			}
+		if ok && row.kind == treeProfileRow {
+			m.ProfilesManager.DeleteProfile(row.profile.Name)
+			m.clampProfileIndex()
 		}
 	case "c":
 		m.State = CreateProfileView
@@ -231,12 +502,24 @@ func (m *MainModel) handleProfileFormKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter":
 		if m.InputIndex == len(m.Inputs)-1 {
-			profile := m.createProfileFromInputs()
-			if profile.Name != "" && profile.BaseURL != "" {
-				m.ProfilesManager.AddProfile(profile)
-				m.State = MainMenuView
-				m.resetInputs()
+			profile, errs := m.validateForm()
+			m.FormErrors = errs
+			if len(errs) > 0 {
+				return m, nil
+			}
+
+			var err error
+			if m.IsEditing {
+				err = m.ProfilesManager.UpdateProfile(m.EditingProfile.Name, profile)
+			} else {
+				err = m.ProfilesManager.AddProfile(profile)
 			}
+			if err != nil {
+				m.FormErrors = map[string]string{"": "Could not save profile: " + err.Error()}
+				return m, nil
+			}
+			m.State = MainMenuView
+			m.resetInputs()
 		} else {
 			m.Inputs[m.InputIndex].Blur()
 			m.InputIndex++
@@ -246,7 +529,12 @@ func (m *MainModel) handleProfileFormKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.Inputs[m.InputIndex].Focus()
 		}
 	}
-	return m, m.updateInputs(msg)
+
+	cmd := m.updateInputs(msg)
+	if len(m.FormErrors) > 0 {
+		_, m.FormErrors = m.validateForm()
+	}
+	return m, cmd
 }
 
 func (m *MainModel) handleRunningKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -257,6 +545,63 @@ func (m *MainModel) handleRunningKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else {
 			return m.startRunning()
 		}
+	case "v":
+		for _, result := range m.PingResults {
+			if result.Changed {
+				m.DiffResult = result
+				m.DiffOffset = 0
+				m.State = DiffView
+				break
+			}
+		}
+	case "r":
+		if m.Daemon != nil {
+			return m, m.triggerCheck()
+		}
+	case "a":
+		return m, m.updateAlert("ack")
+	case "m":
+		return m, m.updateAlert("silence")
+	case "u":
+		return m, m.updateAlert("unsilence")
+	case "p":
+		if m.CurrentProfile.Snapshot != nil && m.Daemon == nil {
+			if err := m.PingService.Snapshots().Pin(m.CurrentProfile); err != nil {
+				m.Notice = "Could not pin baseline: " + err.Error()
+			} else {
+				m.Notice = "Pinned last response as baseline"
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *MainModel) handleGroupRunningKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		if m.IsRunning {
+			m.pauseGroup()
+			return m, nil
+		}
+		return m.startGroup(m.RunSelector)
+	case "r":
+		if m.Daemon != nil {
+			return m, m.fetchGroupResults()
+		}
+	}
+	return m, nil
+}
+
+func (m *MainModel) handleDiffKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.DiffOffset > 0 {
+			m.DiffOffset--
+		}
+	case "down", "j":
+		if m.DiffOffset < len(m.diffHunks())-1 {
+			m.DiffOffset++
+		}
 	}
 	return m, nil
 }
@@ -270,7 +615,8 @@ func (m *MainModel) updateInputs(msg tea.Msg) tea.Cmd {
 }
 
 func (m *MainModel) resetInputs() {
-	m.Inputs = make([]textinput.Model, 6)
+	m.FormErrors = nil
+	m.Inputs = make([]textinput.Model, 8)
 
 	m.Inputs[0] = textinput.New()
 	m.Inputs[0].Placeholder = "Profile name"
@@ -290,6 +636,12 @@ func (m *MainModel) resetInputs() {
 	m.Inputs[5] = textinput.New()
 	m.Inputs[5].Placeholder = "5"
 
+	m.Inputs[6] = textinput.New()
+	m.Inputs[6].Placeholder = "payments/eu"
+
+	m.Inputs[7] = textinput.New()
+	m.Inputs[7].Placeholder = "prod,critical"
+
 	m.Inputs[0].Focus()
 }
 
@@ -313,18 +665,23 @@ func (m *MainModel) populateInputsFromProfile(profile models.Profile) {
 	m.Inputs[4].SetValue(strings.Join(headers, ","))
 
 	m.Inputs[5].SetValue(strconv.Itoa(profile.Interval))
+	m.Inputs[6].SetValue(profile.Group)
+	m.Inputs[7].SetValue(strings.Join(profile.Tags, ","))
 }
 
 func (m *MainModel) createProfileFromInputs() models.Profile {
-	profile := models.Profile{
-		Name:     m.Inputs[0].Value(),
-		BaseURL:  m.Inputs[1].Value(),
-		Route:    m.Inputs[2].Value(),
-		Params:   make(map[string]string),
-		Headers:  make(map[string]string),
-		Interval: 5,
+	var profile models.Profile
+	if m.IsEditing {
+		profile = m.EditingProfile
 	}
 
+	profile.Name = m.Inputs[0].Value()
+	profile.BaseURL = m.Inputs[1].Value()
+	profile.Route = m.Inputs[2].Value()
+	profile.Params = make(map[string]string)
+	profile.Headers = make(map[string]string)
+	profile.Interval = models.DefaultIntervalMinutes
+
 	if paramsStr := m.Inputs[3].Value(); paramsStr != "" {
 		for _, pair := range strings.Split(paramsStr, ",") {
 			if kv := strings.SplitN(strings.TrimSpace(pair), "=", 2); len(kv) == 2 {
@@ -347,56 +704,391 @@ func (m *MainModel) createProfileFromInputs() models.Profile {
 		}
 	}
 
+	profile.Group = strings.TrimSpace(m.Inputs[6].Value())
+	profile.Tags = nil
+	for _, tag := range strings.Split(m.Inputs[7].Value(), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(profile.Tags, tag) {
+			profile.Tags = append(profile.Tags, tag)
+		}
+	}
+
 	return profile
 }
 
+var formFieldNames = []string{"name", "base_url", "route", "params", "headers", "interval", "group", "tags"}
+
+func (m *MainModel) validateForm() (models.Profile, map[string]string) {
+	profile := m.createProfileFromInputs()
+	errs := make(map[string]string)
+
+	if pair := malformedPair(m.Inputs[3].Value()); pair != "" {
+		errs["params"] = fmt.Sprintf("%q is not key=value", pair)
+	}
+	if pair := malformedPair(m.Inputs[4].Value()); pair != "" {
+		errs["headers"] = fmt.Sprintf("%q is not Name=value", pair)
+	}
+	if intervalStr := strings.TrimSpace(m.Inputs[5].Value()); intervalStr != "" {
+		interval, err := strconv.Atoi(intervalStr)
+		if err != nil {
+			errs["interval"] = "must be a whole number of minutes"
+		} else if interval < 0 {
+			errs["interval"] = fmt.Sprintf("must be between 1 and %d minutes, or 0 for the %d-minute default", models.MaxInterval, models.DefaultIntervalMinutes)
+		}
+	}
+
+	var validation models.ValidationErrors
+	if err := profile.Validate(); errors.As(err, &validation) {
+		for _, fieldErr := range validation {
+			if _, ok := errs[fieldErr.Field]; !ok {
+				errs[fieldErr.Field] = fieldErr.Message
+			}
+		}
+	}
+
+	if _, ok := errs["name"]; !ok && (!m.IsEditing || profile.Name != m.EditingProfile.Name) {
+		for _, existing := range m.ProfilesManager.GetProfiles() {
+			if existing.Name == profile.Name {
+				errs["name"] = "a profile with this name already exists"
+				break
+			}
+		}
+	}
+
+	return profile, errs
+}
+
+func malformedPair(value string) string {
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair != "" && !strings.Contains(pair, "=") {
+			return pair
+		}
+	}
+	return ""
+}
+
 func (m *MainModel) startRunning() (tea.Model, tea.Cmd) {
-	m.IsRunning = true
 	m.PingResults = []models.PingResult{}
-	return m, tea.Batch(
-		m.doPing(),
-		m.tick(),
-	)
+	m.Notice = ""
+
+	if m.Daemon != nil {
+		m.IsRunning = true
+		return m, tea.Batch(
+			m.fetchHistory(),
+			m.tick(),
+		)
+	}
+
+	if err := m.Scheduler.Schedule(m.CurrentProfile); err != nil {
+		m.Notice = "Could not schedule checks: " + err.Error()
+		return m, nil
+	}
+	m.IsRunning = true
+
+	if m.listening {
+		return m, nil
+	}
+	m.listening = true
+	return m, m.waitForResult()
+}
+
+func (m *MainModel) startGroup(selector models.ProfileSelector) (tea.Model, tea.Cmd) {
+	profiles := models.SelectProfiles(m.ProfilesManager.GetProfiles(), selector)
+	if len(profiles) == 0 {
+		m.Notice = fmt.Sprintf("No profiles in %s", selector)
+		return m, nil
+	}
+
+	m.RunSelector = selector
+	m.GroupProfiles = profiles
+	m.GroupResults = make(map[string]models.PingResult)
+	m.Notice = ""
+	m.State = GroupRunningView
+
+	if m.Daemon != nil {
+		m.IsRunning = true
+		return m, tea.Batch(
+			m.fetchGroupResults(),
+			m.tick(),
+		)
+	}
+
+	var failed []string
+	for _, profile := range profiles {
+		if err := m.Scheduler.Schedule(profile); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", profile.Name, err))
+		}
+	}
+	if len(failed) > 0 {
+		m.Notice = "Could not schedule checks: " + strings.Join(failed, "; ")
+	}
+	m.IsRunning = true
+
+	if m.listening {
+		return m, nil
+	}
+	m.listening = true
+	return m, m.waitForResult()
+}
+
+func (m *MainModel) pauseGroup() {
+	m.IsRunning = false
+	for _, profile := range m.GroupProfiles {
+		m.Scheduler.Unschedule(profile.Name)
+	}
+}
+
+func (m *MainModel) groupProfile(name string) (models.Profile, bool) {
+	if m.State != GroupRunningView {
+		return models.Profile{}, false
+	}
+	for _, profile := range m.GroupProfiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return models.Profile{}, false
+}
+
+func (m *MainModel) reloadGroup(before, profiles []models.Profile) string {
+	selected := models.SelectProfiles(profiles, m.RunSelector)
+	changed, _ := models.DiffProfiles(before, profiles)
+
+	for _, profile := range m.GroupProfiles {
+		if !slices.ContainsFunc(selected, func(p models.Profile) bool { return p.Name == profile.Name }) {
+			m.Scheduler.Unschedule(profile.Name)
+			delete(m.GroupResults, profile.Name)
+		}
+	}
+	for _, profile := range selected {
+		_, running := m.groupProfile(profile.Name)
+		isChanged := slices.ContainsFunc(changed, func(p models.Profile) bool { return p.Name == profile.Name })
+		if running && !isChanged {
+			continue
+		}
+		if err := m.Scheduler.Schedule(profile); err != nil {
+			m.Notice = "Could not schedule checks: " + err.Error()
+		}
+	}
+	m.GroupProfiles = selected
+
+	if len(selected) == 0 {
+		m.stopRunning()
+		return fmt.Sprintf("; %s no longer has any profiles", m.RunSelector)
+	}
+	return ""
 }
 
 func (m *MainModel) stopRunning() tea.Model {
 	m.IsRunning = false
-	if m.Ticker != nil {
-		m.Ticker.Stop()
-		m.Ticker = nil
+	m.Notice = ""
+	m.Scheduler.Unschedule(m.CurrentProfile.Name)
+	if m.State == GroupRunningView {
+		m.pauseGroup()
+		m.GroupProfiles = nil
 	}
 	m.State = MainMenuView
 	return m
 }
 
+type resultQueue struct {
+	mu      sync.Mutex
+	pending []tea.Msg
+	ready   chan struct{}
+}
+
+func newResultQueue() *resultQueue {
+	return &resultQueue{ready: make(chan struct{}, 1)}
+}
+
+func (q *resultQueue) push(msg tea.Msg) {
+	q.mu.Lock()
+	q.pending = append(q.pending, msg)
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *resultQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *resultQueue) Write(p []byte) (int, error) {
+	q.push(schedulerErrorMsg(strings.TrimSpace(string(p))))
+	return len(p), nil
+}
+
+func (q *resultQueue) next() tea.Msg {
+	for {
+		<-q.ready
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			continue
+		}
+		msg := q.pending[0]
+		q.pending = q.pending[1:]
+		more := len(q.pending) > 0
+		q.mu.Unlock()
+		if more {
+			q.signal()
+		}
+		return msg
+	}
+}
+
+func (m *MainModel) waitForResult() tea.Cmd {
+	results := m.results
+	return func() tea.Msg {
+		return results.next()
+	}
+}
+
+func (m *MainModel) observe(profile models.Profile, result models.PingResult) tea.Cmd {
+	dispatches, err := m.Alerts.Evaluate(profile, result)
+	if err != nil {
+		m.Notice = "Could not save alert state: " + err.Error()
+	}
+	if len(dispatches) == 0 {
+		return nil
+	}
+
+	alerts := m.Alerts
+	return func() tea.Msg {
+		if err := alerts.Send(dispatches); err != nil {
+			return notifyFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+func (m *MainModel) observeRecord(profile models.Profile, result models.PingResult) tea.Cmd {
+	if last, ok := m.observed[profile.Name]; ok && last.Equal(result.Timestamp) {
+		return nil
+	}
+	m.observed[profile.Name] = result.Timestamp
+	return m.observe(profile, result)
+}
+
+func (m *MainModel) updateAlert(action string) tea.Cmd {
+	name := m.CurrentProfile.Name
+
+	var err error
+	switch action {
+	case "ack":
+		err = m.Alerts.Acknowledge(name, alertSnooze)
+		m.Notice = "Alert acknowledged for 1h"
+	case "silence":
+		err = m.Alerts.Silence(name, alertSnooze)
+		m.Notice = "Alerts silenced for 1h"
+	case "unsilence":
+		err = m.Alerts.Unsilence(name)
+		m.Notice = "Alerts resumed"
+	}
+	if err != nil {
+		m.Notice = "Could not update alert: " + err.Error()
+		return nil
+	}
+
+	if m.Daemon == nil {
+		return nil
+	}
+	client := m.Daemon
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case "ack":
+			_, err = client.Acknowledge(name, alertSnooze)
+		case "silence":
+			_, err = client.Silence(name, alertSnooze)
+		case "unsilence":
+			_, err = client.Unsilence(name)
+		}
+		if err != nil {
+			return notifyFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
 func (m *MainModel) tick() tea.Cmd {
 	if !m.IsRunning {
 		return nil
 	}
-	return tea.Tick(time.Duration(m.CurrentProfile.Interval)*time.Minute, func(t time.Time) tea.Msg {
+	return tea.Tick(attachPollInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-func (m *MainModel) doPing() tea.Cmd {
+func (m *MainModel) fetchHistory() tea.Cmd {
+	client, name := m.Daemon, m.CurrentProfile.Name
+	return func() tea.Msg {
+		records, err := client.History(name, 20)
+		return historyMsg{records: records, err: err}
+	}
+}
+
+func (m *MainModel) fetchGroupResults() tea.Cmd {
+	client := m.Daemon
+	names := make([]string, len(m.GroupProfiles))
+	for i, profile := range m.GroupProfiles {
+		names[i] = profile.Name
+	}
+	return func() tea.Msg {
+		results := make(map[string]models.PingResult)
+		for _, name := range names {
+			records, err := client.History(name, 1)
+			if err != nil {
+				return groupResultsMsg{err: err}
+			}
+			if len(records) > 0 {
+				results[name] = records[0].Result()
+			}
+		}
+		return groupResultsMsg{results: results}
+	}
+}
+
+func (m *MainModel) triggerCheck() tea.Cmd {
+	client, name := m.Daemon, m.CurrentProfile.Name
+	return func() tea.Msg {
+		if _, err := client.Check(name); err != nil {
+			return historyMsg{err: err}
+		}
+		records, err := client.History(name, 20)
+		return historyMsg{records: records, err: err}
+	}
+}
+
+func (m *MainModel) refreshProfiles() tea.Cmd {
+	client := m.Daemon
 	return func() tea.Msg {
-		result := m.PingService.Ping(m.CurrentProfile)
-		return pingResultMsg(result)
+		return profilesRefreshedMsg{err: client.Refresh()}
 	}
 }
 
 func (m *MainModel) View() string {
+	view := "Unknown view"
 	switch m.State {
 	case MainMenuView:
-		return m.mainMenuView()
+		view = m.mainMenuView()
 	case ProfileListView:
-		return m.profileListView()
+		view = m.profileListView()
 	case CreateProfileView:
-		return m.profileFormView("Create New Profile")
+		view = m.profileFormView("Create New Profile")
 	case EditProfileView:
-		return m.profileFormView("Edit Profile")
+		view = m.profileFormView("Edit Profile")
 	case RunningView:
-		return m.runningView()
+		view = m.runningView()
+	case GroupRunningView:
+		view = m.groupRunningView()
+	case DiffView:
+		view = m.diffView()
+	}
+
+	if m.Toast != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, toastStyle.Render(m.Toast))
 	}
-	return "Unknown view"
+	return view
 }
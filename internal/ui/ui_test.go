@@ -1,26 +1,38 @@
 package ui
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/route-keeper/internal/daemon"
 	"github.com/lutefd/route-keeper/internal/models"
+	"github.com/lutefd/route-keeper/internal/notify"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMainModel(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	assert.NotNil(t, model)
 	assert.Equal(t, MainMenuView, model.State)
-	assert.Len(t, model.Inputs, 6)
+	assert.Len(t, model.Inputs, 8)
 	assert.Equal(t, "Profile name", model.Inputs[0].Placeholder)
 }
 
 func TestMainModel_Init(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	cmd := model.Init()
@@ -29,7 +41,7 @@ func TestMainModel_Init(t *testing.T) {
 
 func TestMainModel_Update(t *testing.T) {
 	t.Run("WindowSizeMsg", func(t *testing.T) {
-		pm := models.NewProfilesManager()
+		pm := newTestProfilesManager(t)
 		model := NewMainModel(pm)
 
 		msg := tea.WindowSizeMsg{Width: 100, Height: 50}
@@ -41,9 +53,8 @@ func TestMainModel_Update(t *testing.T) {
 		assert.Equal(t, 50, updatedModel.(*MainModel).Height)
 	})
 
-	t.Run("tickMsg when running", func(t *testing.T) {
-		pm := models.NewProfilesManager()
-		model := NewMainModel(pm)
+	t.Run("tickMsg when attached and running", func(t *testing.T) {
+		model := NewAttachedModel(daemon.NewClient(daemon.DefaultAddr))
 		model.IsRunning = true
 
 		msg := tickMsg(time.Now())
@@ -51,11 +62,25 @@ func TestMainModel_Update(t *testing.T) {
 
 		assert.NotNil(t, cmd)
 	})
+
+	t.Run("scheduled results for other profiles are dropped", func(t *testing.T) {
+		pm := newTestProfilesManager(t)
+		model := NewMainModel(pm)
+		model.IsRunning = true
+		model.CurrentProfile = models.Profile{Name: "api"}
+
+		_, cmd := model.Update(scheduledPingMsg{profile: "old", result: models.PingResult{Success: true}})
+		assert.NotNil(t, cmd)
+		assert.Empty(t, model.PingResults)
+
+		model.Update(scheduledPingMsg{profile: "api", result: models.PingResult{Success: true}})
+		assert.Len(t, model.PingResults, 1)
+	})
 }
 
 func TestMainModel_HandleKeyPress(t *testing.T) {
 	t.Run("MainMenuView - up/down navigation", func(t *testing.T) {
-		pm := models.NewProfilesManager()
+		pm := newTestProfilesManager(t)
 		model := NewMainModel(pm)
 		model.State = MainMenuView
 		model.MenuIndex = 1
@@ -72,7 +97,7 @@ func TestMainModel_HandleKeyPress(t *testing.T) {
 	})
 
 	t.Run("MainMenuView - enter key", func(t *testing.T) {
-		pm := models.NewProfilesManager()
+		pm := newTestProfilesManager(t)
 		model := NewMainModel(pm)
 		model.State = MainMenuView
 		model.MenuIndex = 0
@@ -84,7 +109,7 @@ func TestMainModel_HandleKeyPress(t *testing.T) {
 }
 
 func TestMainModel_ProfileForm(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 	model.State = CreateProfileView
 	model.InputIndex = 0
@@ -97,7 +122,7 @@ func TestMainModel_ProfileForm(t *testing.T) {
 }
 
 func TestMainModel_StartStopRunning(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 	model.State = RunningView
 	model.CurrentProfile = models.Profile{
@@ -114,7 +139,7 @@ func TestMainModel_StartStopRunning(t *testing.T) {
 }
 
 func TestMainModel_UpdateInputs(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 	model.State = CreateProfileView
 
@@ -128,7 +153,7 @@ func TestMainModel_UpdateInputs(t *testing.T) {
 }
 
 func TestMainModel_CreateProfileFromInputs(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	model.Inputs[0].SetValue("Test Profile")
@@ -149,7 +174,7 @@ func TestMainModel_CreateProfileFromInputs(t *testing.T) {
 }
 
 func TestMainModel_ResetInputs(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	for i := range model.Inputs {
@@ -169,7 +194,7 @@ func TestMainModel_ResetInputs(t *testing.T) {
 }
 
 func TestMainModel_PopulateInputsFromProfile(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	profile := models.Profile{
@@ -196,7 +221,7 @@ func TestMainModel_PopulateInputsFromProfile(t *testing.T) {
 }
 
 func TestMainModel_View(t *testing.T) {
-	pm := models.NewProfilesManager()
+	pm := newTestProfilesManager(t)
 	model := NewMainModel(pm)
 
 	model.State = MainMenuView
@@ -220,3 +245,458 @@ func TestMainModel_View(t *testing.T) {
 	view = model.View()
 	assert.Contains(t, view, "MONITORING")
 }
+
+func TestMainModel_CreateProfileFromInputs_KeepsUnlistedFields(t *testing.T) {
+	pm := newTestProfilesManager(t)
+	model := NewMainModel(pm)
+
+	model.EditingProfile = models.Profile{
+		Name:    "Scenario",
+		BaseURL: "https://api.example.com",
+		Type:    models.ProfileTypeScenario,
+		Steps:   []models.ScenarioStep{{Name: "login", Route: "/login"}},
+	}
+	model.IsEditing = true
+	model.populateInputsFromProfile(model.EditingProfile)
+	model.Inputs[0].SetValue("Renamed")
+
+	profile := model.createProfileFromInputs()
+
+	assert.Equal(t, "Renamed", profile.Name)
+	assert.Equal(t, models.ProfileTypeScenario, profile.Type)
+	assert.Len(t, profile.Steps, 1)
+}
+
+func TestMainModel_FormIntervalDefault(t *testing.T) {
+	model := NewMainModel(newTestProfilesManager(t))
+	model.Inputs[0].SetValue("api")
+	model.Inputs[1].SetValue("https://api.example.com")
+
+	model.Inputs[5].SetValue("0")
+	profile, errs := model.validateForm()
+	assert.Empty(t, errs)
+	assert.Equal(t, models.DefaultIntervalMinutes, profile.Interval)
+
+	model.Inputs[5].SetValue("-1")
+	_, errs = model.validateForm()
+	assert.Equal(t, "must be between 1 and 1440 minutes, or 0 for the 5-minute default", errs["interval"])
+}
+
+func TestMainModel_FormValidation(t *testing.T) {
+	pm := models.NewProfilesManagerForFile(filepath.Join(t.TempDir(), "profiles.json"))
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "existing", BaseURL: "https://example.com", Interval: 5}))
+	model := NewMainModel(pm)
+	model.State = CreateProfileView
+
+	model.Inputs[0].SetValue("existing")
+	model.Inputs[1].SetValue("api.example.com")
+	model.Inputs[3].SetValue("page=1,broken")
+	model.Inputs[4].SetValue("Bad Header=x")
+	model.Inputs[5].SetValue("5m")
+	model.Inputs[0].Blur()
+	model.Inputs[5].Focus()
+	model.InputIndex = len(model.Inputs) - 1
+
+	model.handleProfileFormKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, CreateProfileView, model.State)
+	assert.Len(t, pm.GetProfiles(), 1)
+	assert.Equal(t, "a profile with this name already exists", model.FormErrors["name"])
+	assert.Equal(t, "must start with http:// or https://", model.FormErrors["base_url"])
+	assert.Equal(t, `"broken" is not key=value`, model.FormErrors["params"])
+	assert.Equal(t, `"Bad Header" is not a valid header name`, model.FormErrors["headers"])
+	assert.Equal(t, "must be a whole number of minutes", model.FormErrors["interval"])
+	assert.Contains(t, model.View(), "✗ must start with http:// or https://")
+
+	model.Inputs[0].SetValue("api")
+	model.Inputs[1].SetValue("https://api.example.com")
+	model.Inputs[3].SetValue("page=1")
+	model.Inputs[4].SetValue("Accept=application/json")
+	model.handleProfileFormKeys(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, map[string]string{}, model.FormErrors)
+
+	model.handleProfileFormKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, MainMenuView, model.State)
+	require.Len(t, pm.GetProfiles(), 2)
+	assert.Equal(t, 5, pm.GetProfiles()[1].Interval)
+}
+
+func TestMainModel_DiffView(t *testing.T) {
+	pm := newTestProfilesManager(t)
+	model := NewMainModel(pm)
+	model.State = RunningView
+	model.CurrentProfile = models.Profile{Name: "config", Snapshot: &models.SnapshotConfig{}}
+	model.PingResults = []models.PingResult{
+		{Success: true},
+		{Success: true, Changed: true, Diff: []models.DiffLine{
+			{Op: models.DiffEqual, Text: "{"},
+			{Op: models.DiffDelete, Text: `  "version": "1"`},
+			{Op: models.DiffInsert, Text: `  "version": "2"`},
+			{Op: models.DiffEqual, Text: "}"},
+		}},
+	}
+
+	assert.Contains(t, model.View(), "v: View diff")
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	assert.Equal(t, DiffView, model.State)
+
+	view := model.View()
+	assert.Contains(t, view, "RESPONSE DIFF")
+	assert.Contains(t, view, `+   "version": "2"`)
+	assert.Contains(t, view, `-   "version": "1"`)
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, RunningView, model.State)
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	assert.Contains(t, model.Notice, "Could not pin baseline")
+}
+
+func TestAttachedModel(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	pm := models.NewProfilesManagerForFile(filepath.Join(t.TempDir(), "profiles.json"))
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "remote", BaseURL: target.URL, Interval: 60}))
+	history, err := daemon.NewHistory("", 0)
+	require.NoError(t, err)
+	api := httptest.NewServer(daemon.NewServer(pm, history).Handler())
+	defer api.Close()
+
+	model := NewAttachedModel(daemon.NewClient(api.URL))
+	assert.Empty(t, model.ProfilesManager.GetProfiles())
+
+	_, cmd := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	model.Update(cmd())
+	require.Len(t, model.ProfilesManager.GetProfiles(), 1)
+
+	model.CurrentProfile = model.ProfilesManager.GetProfiles()[0]
+	model.State = RunningView
+	model.IsRunning = true
+
+	model.Update(model.triggerCheck()())
+	require.Len(t, model.PingResults, 1)
+	assert.True(t, model.PingResults[0].Success)
+	assert.Contains(t, model.View(), "ATTACHED")
+	assert.Contains(t, model.View(), "r: Check now")
+}
+
+func TestMainModel_Alerts(t *testing.T) {
+	var out bytes.Buffer
+	pm := newTestProfilesManager(t)
+	model := NewMainModel(pm)
+	model.Alerts.AddChannel(notify.ChannelTerminal, notify.NewTerminalNotifier(models.NotificationSettings{Bell: true}, &out))
+	model.CurrentProfile = models.Profile{Name: "api"}
+	model.State = RunningView
+	model.IsRunning = true
+
+	_, cmd := model.Update(pingResultMsg(models.PingResult{Success: true}))
+	assert.Nil(t, cmd)
+	assert.NotContains(t, model.View(), "a: Acknowledge")
+
+	_, cmd = model.Update(pingResultMsg(models.PingResult{StatusCode: 500}))
+	require.NotNil(t, cmd)
+	assert.Nil(t, cmd())
+	assert.Equal(t, "\a", out.String())
+	assert.Contains(t, model.View(), "ALERT FIRING")
+	assert.Contains(t, model.View(), "a: Acknowledge 1h")
+
+	_, cmd = model.Update(pingResultMsg(models.PingResult{StatusCode: 500}))
+	assert.Nil(t, cmd)
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	assert.Equal(t, "Alert acknowledged for 1h", model.Notice)
+	assert.Contains(t, model.View(), "ACKNOWLEDGED until")
+	assert.Contains(t, model.View(), "u: Resume alerts")
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	assert.Contains(t, model.View(), "SILENCED until")
+
+	_, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	assert.Contains(t, model.View(), "ALERT FIRING")
+
+	_, cmd = model.Update(pingResultMsg(models.PingResult{Success: true}))
+	require.NotNil(t, cmd)
+	cmd()
+	assert.Equal(t, "\a\a", out.String())
+	assert.NotContains(t, model.View(), "ALERT FIRING")
+}
+
+func TestMainModel_MaintenanceBadge(t *testing.T) {
+	pm := models.NewProfilesManagerForFile(filepath.Join(t.TempDir(), "profiles.json"))
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "api", BaseURL: "http://localhost", Interval: 5}))
+	model := NewMainModel(pm)
+	model.State = ProfileListView
+	assert.NotContains(t, model.View(), "MAINTENANCE")
+
+	model.PingService.SetMaintenanceWindows([]models.MaintenanceWindow{{Name: "upgrade", TimeWindow: models.TimeWindow{Start: "00:00", End: "00:00"}}})
+	assert.Contains(t, model.View(), "MAINTENANCE: upgrade")
+
+	model.CurrentProfile = pm.GetProfiles()[0]
+	model.State = RunningView
+	model.PingResults = []models.PingResult{{StatusCode: 503, Maintenance: "upgrade"}}
+	view := model.View()
+	assert.Contains(t, view, "MAINTENANCE: upgrade")
+	assert.Contains(t, view, "🛠 maintenance")
+}
+
+func TestMainModel_ScheduledChecks(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	pm := newTestProfilesManager(t)
+	model := NewMainModel(pm)
+	model.State = RunningView
+	model.CurrentProfile = models.Profile{Name: "api", BaseURL: target.URL, Interval: 60}
+
+	_, cmd := model.startRunning()
+	require.NotNil(t, cmd)
+	assert.True(t, model.IsRunning)
+
+	model.Update(cmd())
+	require.Len(t, model.PingResults, 1)
+	assert.True(t, model.PingResults[0].Success)
+
+	model.stopRunning()
+	model.CurrentProfile = models.Profile{Name: "cron", BaseURL: target.URL, Schedule: &models.ScheduleConfig{Cron: "61 * * * *"}}
+	model.State = RunningView
+	_, cmd = model.startRunning()
+	assert.Nil(t, cmd)
+	assert.False(t, model.IsRunning)
+	assert.Contains(t, model.Notice, "Could not schedule checks")
+
+	log.New(model.results, "", 0).Printf("Stopped scheduling %s: %v", "api", errors.New("no future runs"))
+	_, cmd = model.Update(model.waitForResult()())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "Stopped scheduling api: no future runs", model.Notice)
+}
+
+func TestMainModel_HotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {"name": "api", "base_url": "http://localhost:1", "interval": 60},
+  {"name": "web", "base_url": "http://localhost:2", "interval": 60}
+]`), 0644))
+	pm := models.NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+
+	model := NewMainModel(pm)
+	defer model.Scheduler.Stop()
+	reload := func() {
+		before := pm.GetProfiles()
+		changed, err := pm.ReloadIfChanged()
+		model.Update(profilesReloadedMsg{before: before, changed: changed, err: err})
+	}
+
+	model.State = RunningView
+	model.CurrentProfile = pm.GetProfiles()[0]
+	model.IsRunning = true
+
+	reload()
+	assert.Empty(t, model.Toast)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {"name": "api", "base_url": "http://localhost:1", "route": "/ready", "interval": 60},
+  {"name": "web", "base_url": "http://localhost:2", "interval": 60}
+]`), 0644))
+	reload()
+	assert.Equal(t, "↻ Profiles reloaded", model.Toast)
+	assert.Equal(t, "/ready", model.CurrentProfile.Route)
+	assert.True(t, model.IsRunning)
+	assert.Contains(t, model.View(), "↻ Profiles reloaded")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "api", "base_url": `), 0644))
+	reload()
+	assert.Contains(t, model.Toast, "⚠ Reload failed: parse ")
+	model.Toast = ""
+	reload()
+	assert.Empty(t, model.Toast)
+
+	model.ProfileIndex = 1
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "web", "base_url": "http://localhost:2", "interval": 60}]`), 0644))
+	reload()
+	assert.Equal(t, "↻ Profiles reloaded; api was removed", model.Toast)
+	assert.False(t, model.IsRunning)
+	assert.Equal(t, MainMenuView, model.State)
+	assert.Equal(t, 0, model.ProfileIndex)
+
+	seq := model.toastSeq
+	model.Update(clearToastMsg(seq - 1))
+	assert.NotEmpty(t, model.Toast)
+	model.Update(clearToastMsg(seq))
+	assert.Empty(t, model.Toast)
+}
+
+func TestMainModel_ProfileTree(t *testing.T) {
+	pm := newTestProfilesManager(t)
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "checkout", BaseURL: "http://localhost:1", Interval: 60, Group: "payments/eu", Tags: []string{"prod"}}))
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "refunds", BaseURL: "http://localhost:2", Interval: 60, Group: "payments"}))
+	require.NoError(t, pm.AddProfile(models.Profile{Name: "solo", BaseURL: "http://localhost:3", Interval: 60, Tags: []string{"prod"}}))
+
+	model := NewMainModel(pm)
+	model.State = ProfileListView
+	labels := func() []string {
+		var labels []string
+		for _, row := range model.profileRows() {
+			labels = append(labels, strings.Repeat(" ", row.depth)+row.label)
+		}
+		return labels
+	}
+	key := func(k string) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		}
+		model.Update(msg)
+	}
+
+	assert.Equal(t, []string{"payments", " eu", "  checkout", " refunds", "solo", "#prod"}, labels())
+
+	key("left")
+	assert.Equal(t, []string{"payments", "solo", "#prod"}, labels())
+
+	model.ProfileIndex = 2
+	key("enter")
+	assert.Equal(t, []string{"payments", "solo", "#prod", " checkout", " solo"}, labels())
+	assert.Equal(t, ProfileListView, model.State)
+
+	model.ProfileIndex = 4
+	key("e")
+	assert.Equal(t, EditProfileView, model.State)
+	assert.Equal(t, "solo", model.EditingProfile.Name)
+	assert.Equal(t, "prod", model.Inputs[7].Value())
+	assert.Contains(t, model.View(), "Tags")
+}
+
+func TestMainModel_GroupMonitoring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {"name": "checkout", "base_url": "http://localhost:1", "interval": 60, "group": "payments/eu"},
+  {"name": "refunds", "base_url": "http://localhost:2", "interval": 60, "group": "payments"},
+  {"name": "solo", "base_url": "http://localhost:3", "interval": 60}
+]`), 0644))
+	pm := models.NewProfilesManagerForFile(path)
+	require.NoError(t, pm.LoadProfiles())
+
+	model := NewMainModel(pm)
+	defer model.Scheduler.Stop()
+	model.State = ProfileListView
+	model.ProfileIndex = 0
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+
+	require.Equal(t, GroupRunningView, model.State)
+	assert.True(t, model.IsRunning)
+	assert.Len(t, model.GroupProfiles, 2)
+
+	model.Update(scheduledPingMsg{profile: "refunds", result: models.PingResult{Success: true, StatusCode: 200}})
+	model.Update(scheduledPingMsg{profile: "solo", result: models.PingResult{Success: true, StatusCode: 200}})
+	assert.Contains(t, model.GroupResults, "refunds")
+	assert.NotContains(t, model.GroupResults, "solo")
+	assert.Contains(t, model.View(), "MONITORING GROUP payments")
+	assert.Contains(t, model.View(), "1 up • 0 down • 1 pending")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {"name": "checkout", "base_url": "http://localhost:1", "interval": 60, "group": "payments/eu"},
+  {"name": "refunds", "base_url": "http://localhost:2", "interval": 60},
+  {"name": "solo", "base_url": "http://localhost:3", "interval": 60, "group": "payments"}
+]`), 0644))
+	before := pm.GetProfiles()
+	changed, err := pm.ReloadIfChanged()
+	require.NoError(t, err)
+	model.Update(profilesReloadedMsg{before: before, changed: changed, err: err})
+
+	var names []string
+	for _, profile := range model.GroupProfiles {
+		names = append(names, profile.Name)
+	}
+	assert.Equal(t, []string{"checkout", "solo"}, names)
+	assert.NotContains(t, model.GroupResults, "refunds")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, MainMenuView, model.State)
+	assert.False(t, model.IsRunning)
+	assert.Nil(t, model.GroupProfiles)
+}
+
+func TestMainModel_GroupMonitoringKeepsEveryResult(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	pm := newTestProfilesManager(t)
+	for i := range 40 {
+		require.NoError(t, pm.AddProfile(models.Profile{Name: fmt.Sprintf("node-%02d", i), BaseURL: target.URL, Interval: 60, Group: "fleet"}))
+	}
+
+	model := NewMainModel(pm)
+	defer model.Scheduler.Stop()
+	_, cmd := model.startGroup(models.ProfileSelector{Group: "fleet"})
+	require.NotNil(t, cmd)
+	require.Len(t, model.GroupProfiles, 40)
+	require.Eventually(t, func() bool {
+		model.results.mu.Lock()
+		defer model.results.mu.Unlock()
+		return len(model.results.pending) == 40
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for range 40 {
+		msgs := make(chan tea.Msg, 1)
+		go func() { msgs <- model.waitForResult()() }()
+		select {
+		case msg := <-msgs:
+			model.Update(msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d of 40 results", len(model.GroupResults))
+		}
+	}
+	assert.Len(t, model.GroupResults, 40)
+	assert.Contains(t, model.View(), "40 up • 0 down • 0 pending")
+}
+
+func TestAttachedModel_ObservesEachRecordOnce(t *testing.T) {
+	model := NewAttachedModel(daemon.NewClient(daemon.DefaultAddr))
+	model.State = RunningView
+	model.CurrentProfile = models.Profile{Name: "api"}
+	model.IsRunning = true
+
+	failed := historyMsg{records: []models.PingRecord{{Profile: "api", Timestamp: time.Now(), Error: "connection refused"}}}
+	model.Update(failed)
+	model.Update(failed)
+	assert.Equal(t, 1, model.Alerts.State("api").Failures)
+
+	failed.records[0].Timestamp = failed.records[0].Timestamp.Add(time.Minute)
+	model.Update(failed)
+	assert.Equal(t, 2, model.Alerts.State("api").Failures)
+}
+
+func TestAttachedModel_GroupObservesEachRecordOnce(t *testing.T) {
+	model := NewAttachedModel(daemon.NewClient(daemon.DefaultAddr))
+	model.State = GroupRunningView
+	model.IsRunning = true
+	model.GroupProfiles = []models.Profile{{Name: "api"}, {Name: "web"}}
+	model.GroupResults = make(map[string]models.PingResult)
+
+	failed := groupResultsMsg{results: map[string]models.PingResult{
+		"api": {Timestamp: time.Now(), Error: errors.New("connection refused")},
+	}}
+	model.Update(failed)
+	model.Update(failed)
+	assert.Equal(t, 1, model.Alerts.State("api").Failures)
+	assert.Equal(t, 0, model.Alerts.State("web").Failures)
+}
+
+func newTestProfilesManager(t *testing.T) *models.ProfilesManager {
+	t.Setenv(models.HomeEnv, t.TempDir())
+	pm, err := models.NewProfilesManager()
+	require.NoError(t, err)
+	return pm
+}
@@ -2,9 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/route-keeper/internal/models"
 )
 
 func (m *MainModel) mainMenuView() string {
@@ -73,9 +77,9 @@ func (m *MainModel) mainMenuView() string {
 
 func (m *MainModel) profileListView() string {
 	header := headerStyle.Render("📋 SELECT PROFILE")
-	profiles := m.ProfilesManager.GetProfiles()
+	rows := m.profileRows()
 
-	if len(profiles) == 0 {
+	if len(rows) == 0 {
 		empty := lipgloss.NewStyle().
 			Foreground(primaryColor).
 			Italic(true).
@@ -87,6 +91,13 @@ func (m *MainModel) profileListView() string {
 			dimTextStyle.Render("Press 'c' to create a new profile"),
 			dimTextStyle.Render("or press Esc to go back"),
 		)
+		if m.Notice != "" {
+			instructions = lipgloss.JoinVertical(
+				lipgloss.Left,
+				instructions,
+				subtitleStyle.Render(m.Notice),
+			)
+		}
 
 		content := lipgloss.JoinVertical(
 			lipgloss.Center,
@@ -103,14 +114,26 @@ func (m *MainModel) profileListView() string {
 	}
 
 	var profileItems []string
-	for i, profile := range profiles {
+	for i, row := range rows {
+		if row.kind != treeProfileRow {
+			profileItems = append(profileItems, m.folderRowView(row, i == m.ProfileIndex))
+			continue
+		}
+
+		profile := row.profile
 		status := statusInactiveStyle.Render("○")
 		if i == m.ProfileIndex {
 			status = statusActiveStyle.Render("●")
 		}
 
-		url := profile.GetFullURL()
-		interval := fmt.Sprintf("⏱  every %d min", profile.Interval)
+		url := profile.Target()
+		interval := "⏱  " + scheduleLabel(profile)
+		if badge := m.maintenanceBadge(profile); badge != "" {
+			interval += "  " + badge
+		}
+		if len(profile.Tags) > 0 {
+			interval += "  " + tagLabel(profile.Tags)
+		}
 
 		profileCard := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder(), false, false, false, false).
@@ -152,13 +175,19 @@ func (m *MainModel) profileListView() string {
 				Render(profileCard)
 		}
 
-		profileItems = append(profileItems, profileCard)
+		profileItems = append(profileItems, lipgloss.NewStyle().MarginLeft(row.depth*2).Render(profileCard))
 	}
 
 	instructions := lipgloss.JoinHorizontal(
 		lipgloss.Left,
-		dimTextStyle.Render("Enter: Run"),
+		dimTextStyle.Render("Enter: Run/Expand"),
 		lipgloss.NewStyle().Margin(0, 2).Render("•"),
+		dimTextStyle.Render("←/→: Collapse/Expand"),
+		lipgloss.NewStyle().Margin(0, 2).Render("•"),
+		dimTextStyle.Render("g: Run group/tag"),
+	)
+	instructions = lipgloss.JoinVertical(lipgloss.Left, instructions, lipgloss.JoinHorizontal(
+		lipgloss.Left,
 		dimTextStyle.Render("e: Edit"),
 		lipgloss.NewStyle().Margin(0, 2).Render("•"),
 		dimTextStyle.Render("d: Delete"),
@@ -166,7 +195,15 @@ func (m *MainModel) profileListView() string {
 		dimTextStyle.Render("c: Create New"),
 		lipgloss.NewStyle().Margin(0, 2).Render("•"),
 		dimTextStyle.Render("Esc: Back"),
-	)
+	))
+
+	if m.Notice != "" {
+		instructions = lipgloss.JoinVertical(
+			lipgloss.Left,
+			subtitleStyle.Render(m.Notice),
+			instructions,
+		)
+	}
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -183,6 +220,136 @@ func (m *MainModel) profileListView() string {
 		Render(content)
 }
 
+func (m *MainModel) folderRowView(row treeRow, selected bool) string {
+	arrow := "▸"
+	if m.isOpen(row.key) {
+		arrow = "▾"
+	}
+	icon := "📁"
+	if row.kind == treeTagRow {
+		icon = "🏷 "
+	}
+
+	label := fmt.Sprintf("%s %s %s", arrow, icon, row.label)
+	count := dimTextStyle.Render(fmt.Sprintf("(%d)", row.count))
+
+	style := lipgloss.NewStyle().Foreground(primaryColor)
+	prefix := "  "
+	if selected {
+		style = style.Bold(true)
+		prefix = "→ "
+	}
+	return lipgloss.NewStyle().
+		Margin(0, 0, 1, row.depth*2).
+		Render(prefix + style.Render(label) + " " + count)
+}
+
+func tagLabel(tags []string) string {
+	labels := make([]string, len(tags))
+	for i, tag := range tags {
+		labels[i] = "#" + tag
+	}
+	return strings.Join(labels, " ")
+}
+
+func (m *MainModel) groupRunningView() string {
+	kind := "GROUP"
+	if m.RunSelector.Tag != "" {
+		kind = "TAG"
+	}
+	header := headerStyle.Render(fmt.Sprintf("🔄 MONITORING %s %s", kind, m.RunSelector))
+
+	var status string
+	switch {
+	case m.IsRunning && m.Daemon != nil:
+		status = statusActiveStyle.Render("● ATTACHED - Following daemon at " + m.Daemon.Addr())
+	case m.IsRunning:
+		status = statusActiveStyle.Render(fmt.Sprintf("● ACTIVE - Monitoring %d profile(s)...", len(m.GroupProfiles)))
+	default:
+		status = statusInactiveStyle.Render("● PAUSED - Monitoring paused")
+	}
+
+	up, down := 0, 0
+	var lines []string
+	for _, profile := range m.GroupProfiles {
+		result, ok := m.GroupResults[profile.Name]
+		icon := statusInactiveStyle.Render("○")
+		detail := dimTextStyle.Italic(true).Render("waiting for first check...")
+		if ok {
+			if result.Success {
+				up++
+				icon = successStyle.Render("✓")
+				detail = successStyle.Render(resultSummary(result))
+			} else {
+				down++
+				icon = errorStyle.Render("✗")
+				detail = errorStyle.Render(resultSummary(result))
+				if result.Error != nil {
+					detail = errorStyle.Render("ERROR: " + result.Error.Error())
+				}
+			}
+			detail += " " + dimTextStyle.Render(fmt.Sprintf("(%v at %s)",
+				result.Duration.Truncate(time.Millisecond), result.Timestamp.Format("15:04:05")))
+		}
+
+		name := lipgloss.NewStyle().Bold(true).Width(24).Render(profile.Name)
+		line := lipgloss.JoinHorizontal(lipgloss.Left, icon, " ", name, " ", detail)
+		if badge := m.maintenanceBadge(profile); badge != "" {
+			line = lipgloss.JoinHorizontal(lipgloss.Left, line, " ", badge)
+		}
+		if alert := m.Alerts.State(profile.Name); alert.Firing() {
+			line = lipgloss.JoinHorizontal(lipgloss.Left, line, " ", errorStyle.Render("🔔"))
+		}
+		lines = append(lines, line)
+	}
+
+	summary := dimTextStyle.Render(fmt.Sprintf("%d up • %d down • %d pending", up, down, len(m.GroupProfiles)-up-down))
+	table := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Margin(1, 0, 1, 0).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	toggle := "s: Start"
+	if m.IsRunning {
+		toggle = "s: Stop"
+	}
+	instructionItems := []string{dimTextStyle.Render(toggle)}
+	if m.Daemon != nil {
+		instructionItems = append(instructionItems,
+			lipgloss.NewStyle().Margin(0, 2).Render("•"),
+			dimTextStyle.Render("r: Refresh"),
+		)
+	}
+	instructionItems = append(instructionItems,
+		lipgloss.NewStyle().Margin(0, 2).Render("•"),
+		dimTextStyle.Render("Esc/q: Exit"),
+	)
+	instructions := lipgloss.JoinHorizontal(lipgloss.Left, instructionItems...)
+	if m.Notice != "" {
+		instructions = lipgloss.JoinVertical(
+			lipgloss.Left,
+			subtitleStyle.Render(m.Notice),
+			instructions,
+		)
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		status,
+		summary,
+		table,
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Padding(2, 4).
+		Render(content)
+}
+
 func (m *MainModel) profileFormView(title string) string {
 	header := headerStyle.Render("⚙️  " + title)
 
@@ -195,7 +362,9 @@ func (m *MainModel) profileFormView(title string) string {
 		{"Route", "The API endpoint route (e.g., /health)"},
 		{"URL Params", "Optional query parameters (e.g., key1=value1&key2=value2)"},
 		{"Headers", "Request headers (e.g., Authorization=Bearer token)"},
-		{"Interval (minutes)", "How often to check the endpoint (minimum 1 minute)"},
+		{"Interval (minutes)", "How often to check the endpoint (empty or 0 for the 5-minute default)"},
+		{"Group", "Optional folder, nested with / (e.g., payments/eu)"},
+		{"Tags", "Optional comma-separated tags (e.g., prod,critical)"},
 	}
 
 	var formFields []string
@@ -212,14 +381,17 @@ func (m *MainModel) profileFormView(title string) string {
 		if isFocused {
 			inputStyleToUse = focusedInputStyle
 		}
-		formField := lipgloss.JoinVertical(
-			lipgloss.Left,
+		lines := []string{
 			labelStyle.Render(field.label),
 			dimTextStyle.Italic(true).Render(field.description),
 			inputStyleToUse.Render(inputField),
-		)
-		formFields = append(formFields, formField)
+		}
+		if msg := m.FormErrors[formFieldNames[i]]; msg != "" {
+			lines = append(lines, errorStyle.Render("✗ "+msg))
+		}
+		formFields = append(formFields, lipgloss.JoinVertical(lipgloss.Left, lines...))
 	}
+	formFields = append(formFields, m.formErrorsView()...)
 
 	saveButtonLabel := "Save Profile"
 	if m.InputIndex == len(fields) {
@@ -266,7 +438,14 @@ func (m *MainModel) runningView() string {
 	header := headerStyle.Render("🔄 MONITORING")
 
 	var status string
-	if m.IsRunning {
+	if m.IsRunning && m.Daemon != nil {
+		status = lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			statusActiveStyle.Render("●"),
+			" ",
+			statusActiveStyle.Render("ATTACHED - Following daemon at "+m.Daemon.Addr()),
+		)
+	} else if m.IsRunning {
 		status = lipgloss.JoinHorizontal(
 			lipgloss.Left,
 			statusActiveStyle.Render("●"),
@@ -282,6 +461,13 @@ func (m *MainModel) runningView() string {
 		)
 	}
 
+	if badge := m.maintenanceBadge(m.CurrentProfile); badge != "" {
+		status = lipgloss.JoinVertical(lipgloss.Left, status, badge)
+	}
+	if alert := m.alertView(); alert != "" {
+		status = lipgloss.JoinVertical(lipgloss.Left, status, alert)
+	}
+
 	profileCard := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(borderColor).
@@ -292,14 +478,14 @@ func (m *MainModel) runningView() string {
 				lipgloss.Left,
 				lipgloss.NewStyle().Bold(true).Render(m.CurrentProfile.Name),
 				"",
-				dimTextStyle.Render("URL:"),
-				normalTextStyle.Render(m.CurrentProfile.GetFullURL()),
+				dimTextStyle.Render(targetLabel(m.CurrentProfile)),
+				normalTextStyle.Render(m.CurrentProfile.Target()),
 				"",
 				lipgloss.JoinHorizontal(
 					lipgloss.Left,
-					dimTextStyle.Render("Interval:"),
+					dimTextStyle.Render("Schedule:"),
 					" ",
-					normalTextStyle.Render(fmt.Sprintf("%d minutes", m.CurrentProfile.Interval)),
+					normalTextStyle.Render(scheduleLabel(m.CurrentProfile)),
 				),
 			),
 		)
@@ -320,15 +506,21 @@ func (m *MainModel) runningView() string {
 			}
 			timestamp := result.Timestamp.Format("15:04:05")
 			var statusIcon, statusText string
-			if result.Error == nil && result.StatusCode >= 200 && result.StatusCode < 300 {
+			if result.Success {
 				statusIcon = successStyle.Render("✓")
-				statusText = successStyle.Render(fmt.Sprintf("HTTP %d", result.StatusCode))
+				statusText = successStyle.Render(resultSummary(result))
 			} else {
 				statusIcon = errorStyle.Render("✗")
-				if result.Error != nil {
+				if result.AuthError != nil {
+					statusText = errorStyle.Render("AUTH ERROR: " + result.AuthError.Error())
+				} else if len(result.GraphQLErrors) > 0 {
+					statusText = errorStyle.Render(fmt.Sprintf("GRAPHQL: %d error(s)", len(result.GraphQLErrors)))
+				} else if len(result.SchemaViolations) > 0 {
+					statusText = errorStyle.Render(fmt.Sprintf("SCHEMA: %d violation(s)", len(result.SchemaViolations)))
+				} else if result.Error != nil {
 					statusText = errorStyle.Render("ERROR: " + result.Error.Error())
 				} else {
-					statusText = errorStyle.Render(fmt.Sprintf("HTTP %d", result.StatusCode))
+					statusText = errorStyle.Render(resultSummary(result))
 				}
 			}
 			duration := dimTextStyle.Render(fmt.Sprintf("(%v)", result.Duration.Truncate(time.Millisecond)))
@@ -342,8 +534,28 @@ func (m *MainModel) runningView() string {
 				" ",
 				duration,
 			)
+			if result.Maintenance != "" {
+				resultLine = lipgloss.JoinHorizontal(
+					lipgloss.Left,
+					resultLine,
+					" ",
+					dimTextStyle.Render("🛠 maintenance"),
+				)
+			}
+			if result.Changed {
+				resultLine = lipgloss.JoinHorizontal(
+					lipgloss.Left,
+					resultLine,
+					" ",
+					changedStyle.Render("⚠ changed"),
+				)
+			}
 			resultLines = append(resultLines, resultLine)
 		}
+		resultLines = append(resultLines, m.timingsView(m.PingResults[0])...)
+		resultLines = append(resultLines, m.stepResultsView(m.PingResults[0])...)
+		resultLines = append(resultLines, m.schemaViolationsView(m.PingResults[0])...)
+		resultLines = append(resultLines, m.graphQLErrorsView(m.PingResults[0])...)
 		resultsView = lipgloss.JoinVertical(
 			lipgloss.Left,
 			append([]string{resultsHeader}, resultLines...)...,
@@ -352,20 +564,62 @@ func (m *MainModel) runningView() string {
 		resultsView = dimTextStyle.Italic(true).Render("No ping results yet...")
 	}
 
-	var instructions string
+	toggle := "s: Start"
 	if m.IsRunning {
-		instructions = lipgloss.JoinHorizontal(
-			lipgloss.Left,
-			dimTextStyle.Render("s: Stop"),
+		toggle = "s: Stop"
+	}
+	instructionItems := []string{dimTextStyle.Render(toggle)}
+	if m.Daemon != nil {
+		instructionItems = append(instructionItems,
 			lipgloss.NewStyle().Margin(0, 2).Render("•"),
-			dimTextStyle.Render("Esc/q: Exit"),
+			dimTextStyle.Render("r: Check now"),
 		)
-	} else {
-		instructions = lipgloss.JoinHorizontal(
-			lipgloss.Left,
-			dimTextStyle.Render("s: Start"),
+	}
+	if m.CurrentProfile.Snapshot != nil {
+		instructionItems = append(instructionItems,
 			lipgloss.NewStyle().Margin(0, 2).Render("•"),
-			dimTextStyle.Render("Esc/q: Exit"),
+			dimTextStyle.Render("v: View diff"),
+		)
+		if m.Daemon == nil {
+			instructionItems = append(instructionItems,
+				lipgloss.NewStyle().Margin(0, 2).Render("•"),
+				dimTextStyle.Render("p: Pin baseline"),
+			)
+		}
+	}
+	instructionItems = append(instructionItems,
+		lipgloss.NewStyle().Margin(0, 2).Render("•"),
+		dimTextStyle.Render("Esc/q: Exit"),
+	)
+	instructions := lipgloss.JoinHorizontal(lipgloss.Left, instructionItems...)
+
+	alertState := m.Alerts.State(m.CurrentProfile.Name)
+	var alertKeys []string
+	if alertState.Firing() {
+		alertKeys = append(alertKeys, "a: Acknowledge 1h")
+	}
+	alertKeys = append(alertKeys, "m: Silence 1h")
+	if !alertState.AckUntil.IsZero() || !alertState.SilencedUntil.IsZero() {
+		alertKeys = append(alertKeys, "u: Resume alerts")
+	}
+	var alertItems []string
+	for i, key := range alertKeys {
+		if i > 0 {
+			alertItems = append(alertItems, lipgloss.NewStyle().Margin(0, 2).Render("•"))
+		}
+		alertItems = append(alertItems, dimTextStyle.Render(key))
+	}
+	instructions = lipgloss.JoinVertical(
+		lipgloss.Left,
+		instructions,
+		lipgloss.JoinHorizontal(lipgloss.Left, alertItems...),
+	)
+
+	if m.Notice != "" {
+		instructions = lipgloss.JoinVertical(
+			lipgloss.Left,
+			subtitleStyle.Render(m.Notice),
+			instructions,
 		)
 	}
 
@@ -387,3 +641,269 @@ func (m *MainModel) runningView() string {
 		MaxWidth(80).
 		Render(content)
 }
+
+func (m *MainModel) formErrorsView() []string {
+	var keys []string
+	for key := range m.FormErrors {
+		if !slices.Contains(formFieldNames, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		msg := m.FormErrors[key]
+		if key != "" {
+			msg = key + ": " + msg
+		}
+		lines = append(lines, errorStyle.Render("✗ "+msg))
+	}
+	if len(lines) > 0 {
+		lines = append([]string{""}, lines...)
+	}
+	return lines
+}
+
+func scheduleLabel(profile models.Profile) string {
+	label := fmt.Sprintf("every %d min", profile.Interval)
+	if profile.Schedule == nil {
+		return label
+	}
+	if profile.Schedule.Cron != "" {
+		label = "cron " + profile.Schedule.Cron
+	}
+	if len(profile.Schedule.ActiveHours) > 0 {
+		label += " (active hours)"
+	}
+	if profile.Schedule.JitterSeconds > 0 {
+		label += fmt.Sprintf(" (jitter %ds)", profile.Schedule.JitterSeconds)
+	}
+	return label
+}
+
+func (m *MainModel) maintenanceBadge(profile models.Profile) string {
+	window, ok := m.PingService.InMaintenance(profile, time.Now())
+	if !ok {
+		return ""
+	}
+	return changedStyle.Render("🛠 MAINTENANCE: " + window.Label())
+}
+
+func (m *MainModel) alertView() string {
+	now := time.Now()
+	state := m.Alerts.State(m.CurrentProfile.Name)
+
+	switch {
+	case state.Silenced(now):
+		return changedStyle.Render("🔕 SILENCED until " + state.SilencedUntil.Local().Format("15:04"))
+	case state.Firing() && state.Acknowledged(now):
+		return changedStyle.Render("✔ ACKNOWLEDGED until " + state.AckUntil.Local().Format("15:04"))
+	case state.Firing():
+		return errorStyle.Render(fmt.Sprintf("🔔 ALERT FIRING since %s (%d failures)", state.DownSince.Local().Format("15:04"), state.Failures))
+	default:
+		return ""
+	}
+}
+
+func (m *MainModel) timingsView(result models.PingResult) []string {
+	if len(result.Timings) == 0 {
+		return nil
+	}
+
+	var parts []string
+	for i, timing := range result.Timings {
+		if i > 0 {
+			parts = append(parts, lipgloss.NewStyle().Margin(0, 1).Render("•"))
+		}
+		parts = append(parts,
+			dimTextStyle.Render(timing.Name+" "),
+			normalTextStyle.Render(timing.Duration.Truncate(time.Millisecond).String()),
+		)
+	}
+	return []string{"", lipgloss.JoinHorizontal(lipgloss.Left, parts...)}
+}
+
+func (m *MainModel) stepResultsView(result models.PingResult) []string {
+	if len(result.Steps) == 0 {
+		return nil
+	}
+
+	lines := []string{"", dimTextStyle.Render("Last run steps:")}
+	for _, step := range result.Steps {
+		icon := successStyle.Render("✓")
+		detail := dimTextStyle.Render(fmt.Sprintf("HTTP %d", step.StatusCode))
+		if !step.Success {
+			icon = errorStyle.Render("✗")
+			if step.Error != nil {
+				detail = errorStyle.Render(step.Error.Error())
+			}
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			"  ",
+			icon,
+			" ",
+			normalTextStyle.Render(step.Name),
+			" ",
+			detail,
+			" ",
+			dimTextStyle.Render(fmt.Sprintf("(%v)", step.Duration.Truncate(time.Millisecond))),
+		))
+	}
+	return lines
+}
+
+func (m *MainModel) schemaViolationsView(result models.PingResult) []string {
+	if len(result.SchemaViolations) == 0 {
+		return nil
+	}
+
+	lines := []string{"", dimTextStyle.Render("Schema violations:")}
+	for i, violation := range result.SchemaViolations {
+		if i >= 5 {
+			lines = append(lines, dimTextStyle.Render(fmt.Sprintf("  … and %d more", len(result.SchemaViolations)-i)))
+			break
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			"  ",
+			errorStyle.Render("✗"),
+			" ",
+			normalTextStyle.Render(violation.Path),
+			" ",
+			dimTextStyle.Render(violation.Message),
+		))
+	}
+	return lines
+}
+
+const diffContextLines = 2
+
+func (m *MainModel) diffHunks() []string {
+	diff := m.DiffResult.Diff
+
+	show := make([]bool, len(diff))
+	for i, line := range diff {
+		if line.Op == models.DiffEqual {
+			continue
+		}
+		for j := max(0, i-diffContextLines); j <= min(len(diff)-1, i+diffContextLines); j++ {
+			show[j] = true
+		}
+	}
+
+	var lines []string
+	for i, line := range diff {
+		if !show[i] {
+			if i > 0 && show[i-1] {
+				lines = append(lines, dimTextStyle.Render("  ⋯"))
+			}
+			continue
+		}
+		switch line.Op {
+		case models.DiffInsert:
+			lines = append(lines, successStyle.Render("+ "+line.Text))
+		case models.DiffDelete:
+			lines = append(lines, errorStyle.Render("- "+line.Text))
+		default:
+			lines = append(lines, dimTextStyle.Render("  "+line.Text))
+		}
+	}
+	return lines
+}
+
+func (m *MainModel) diffView() string {
+	header := headerStyle.Render("🔍 RESPONSE DIFF")
+
+	summary := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render(m.CurrentProfile.Name),
+		"  ",
+		dimTextStyle.Render(m.DiffResult.Timestamp.Format("15:04:05")),
+	)
+
+	hunks := m.diffHunks()
+	pageSize := 20
+	if m.Height > 12 {
+		pageSize = m.Height - 12
+	}
+	end := min(len(hunks), m.DiffOffset+pageSize)
+	visible := hunks[min(m.DiffOffset, end):end]
+
+	var body string
+	if len(visible) == 0 {
+		body = dimTextStyle.Italic(true).Render("No differences")
+	} else {
+		body = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(borderColor).
+			Padding(0, 1).
+			Render(lipgloss.JoinVertical(lipgloss.Left, visible...))
+	}
+
+	instructions := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		dimTextStyle.Render("↑/↓: Scroll"),
+		lipgloss.NewStyle().Margin(0, 2).Render("•"),
+		dimTextStyle.Render("Esc/q: Back"),
+	)
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		summary,
+		"",
+		body,
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Padding(2, 4).
+		MaxWidth(100).
+		Render(content)
+}
+
+func targetLabel(profile models.Profile) string {
+	if profile.UsesURL() {
+		return "URL:"
+	}
+	return "Target:"
+}
+
+func resultSummary(result models.PingResult) string {
+	if result.StatusCode > 0 {
+		return fmt.Sprintf("HTTP %d", result.StatusCode)
+	}
+	if result.Detail != "" {
+		return result.Detail
+	}
+	if result.Success {
+		return "OK"
+	}
+	return "FAILED"
+}
+
+func (m *MainModel) graphQLErrorsView(result models.PingResult) []string {
+	if len(result.GraphQLErrors) == 0 {
+		return nil
+	}
+
+	lines := []string{"", dimTextStyle.Render("GraphQL errors:")}
+	for i, message := range result.GraphQLErrors {
+		if i >= 5 {
+			lines = append(lines, dimTextStyle.Render(fmt.Sprintf("  … and %d more", len(result.GraphQLErrors)-i)))
+			break
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			"  ",
+			errorStyle.Render("✗"),
+			" ",
+			normalTextStyle.Render(message),
+		))
+	}
+	return lines
+}